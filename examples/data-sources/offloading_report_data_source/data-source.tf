@@ -0,0 +1,9 @@
+# Report the offloaded namespaces of the cluster.
+data "liqo_offloading_report" "report" {}
+
+output "offloaded_pods" {
+  value = {
+    for ns in data.liqo_offloading_report.report.namespaces :
+    ns.namespace => { for c in ns.clusters : c.cluster_id => c.pods }
+  }
+}
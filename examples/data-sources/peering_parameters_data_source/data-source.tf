@@ -0,0 +1,2 @@
+# Retrieve peer parameters.
+data "liqo_peering_parameters" "parameters" {}
@@ -0,0 +1,8 @@
+# Retrieve the resources offered by a peered cluster.
+data "liqo_resource_offer" "offer" {
+  cluster_id = liqo_peer.peering.cluster_id
+}
+
+output "offered_cpu" {
+  value = data.liqo_resource_offer.offer.cpu
+}
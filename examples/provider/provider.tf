@@ -3,4 +3,18 @@ provider "liqo" {
   kubernetes = {
     config_path = "path/to/kubeconfig"
   }
-}
\ No newline at end of file
+}
+# Manage several clusters with a single provider, selecting them through the cluster attribute.
+provider "liqo" {
+  alias = "fleet"
+  clusters = {
+    prod-eu = {
+      config_path    = "path/to/kubeconfig"
+      config_context = "prod-eu"
+    }
+    prod-us = {
+      config_path    = "path/to/kubeconfig"
+      config_context = "prod-us"
+    }
+  }
+}
@@ -0,0 +1,3 @@
+# Cluster labels can be imported given the Liqo namespace, optionally prefixed by the provider clusters entry.
+terraform import liqo_cluster_labels.labels liqo
+terraform import liqo_cluster_labels.labels prod-eu/liqo
@@ -0,0 +1,6 @@
+resource "liqo_cluster_labels" "labels" {
+  labels = {
+    "topology.kubernetes.io/region" = "eu-west"
+    "liqo.io/provider"              = "on-prem"
+  }
+}
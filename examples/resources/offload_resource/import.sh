@@ -0,0 +1,3 @@
+# Offloadings can be imported given the offloaded namespace, optionally prefixed by the provider clusters entry.
+terraform import liqo_offload.offload <namespace>
+terraform import liqo_offload.offload prod-eu/<namespace>
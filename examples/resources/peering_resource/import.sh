@@ -0,0 +1,3 @@
+# Peerings can be imported given the ID of the remote cluster, optionally prefixed by the provider clusters entry.
+terraform import liqo_peer.peer <cluster_id>
+terraform import liqo_peer.peer prod-eu/<cluster_id>
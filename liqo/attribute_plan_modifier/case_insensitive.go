@@ -0,0 +1,45 @@
+package planmodifier
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type caseInsensitiveAttributePlanModifier struct{}
+
+// CaseInsensitive used to keep the prior value of an attribute configured again with a different case (e.g., nodeport for NodePort).
+// Terraform only accepts a planned value equal to either the configuration or the prior state, hence a configuration
+// which differs from the prior state other than in the case is planned as is.
+func CaseInsensitive() tfsdk.AttributePlanModifier {
+	return &caseInsensitiveAttributePlanModifier{}
+}
+
+var _ tfsdk.AttributePlanModifier = (*caseInsensitiveAttributePlanModifier)(nil)
+
+func (apm *caseInsensitiveAttributePlanModifier) Description(ctx context.Context) string {
+	return apm.MarkdownDescription(ctx)
+}
+
+func (apm *caseInsensitiveAttributePlanModifier) MarkdownDescription(_ context.Context) string {
+	return "Matches the prior value ignoring the case"
+}
+
+//nolint:gocritic,lll // Terraform Framework template code
+func (apm *caseInsensitiveAttributePlanModifier) Modify(_ context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
+	config, ok := req.AttributeConfig.(types.String)
+	if !ok || config.IsNull() || config.IsUnknown() {
+		return
+	}
+
+	state, ok := req.AttributeState.(types.String)
+	if !ok || state.IsNull() || state.IsUnknown() {
+		return
+	}
+
+	if strings.EqualFold(config.ValueString(), state.ValueString()) {
+		res.AttributePlan = state
+	}
+}
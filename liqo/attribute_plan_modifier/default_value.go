@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type defaultValueAttributePlanModifier struct {
@@ -18,6 +19,41 @@ func DefaultValue(v attr.Value) tfsdk.AttributePlanModifier {
 	return &defaultValueAttributePlanModifier{v}
 }
 
+// DefaultString used to set a DefaultValue to String attributes.
+func DefaultString(v string) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.StringValue(v))
+}
+
+// DefaultBool used to set a DefaultValue to Bool attributes.
+func DefaultBool(v bool) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.BoolValue(v))
+}
+
+// DefaultInt64 used to set a DefaultValue to Int64 attributes.
+func DefaultInt64(v int64) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.Int64Value(v))
+}
+
+// DefaultList used to set a DefaultValue to List attributes.
+func DefaultList(elemType attr.Type, elems []attr.Value) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.ListValueMust(elemType, elems))
+}
+
+// DefaultSet used to set a DefaultValue to Set attributes.
+func DefaultSet(elemType attr.Type, elems []attr.Value) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.SetValueMust(elemType, elems))
+}
+
+// DefaultMap used to set a DefaultValue to Map attributes.
+func DefaultMap(elemType attr.Type, elems map[string]attr.Value) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.MapValueMust(elemType, elems))
+}
+
+// DefaultObject used to set a DefaultValue to Object attributes, including single nested ones.
+func DefaultObject(attrTypes map[string]attr.Type, attrs map[string]attr.Value) tfsdk.AttributePlanModifier {
+	return DefaultValue(types.ObjectValueMust(attrTypes, attrs))
+}
+
 var _ tfsdk.AttributePlanModifier = (*defaultValueAttributePlanModifier)(nil)
 
 func (apm *defaultValueAttributePlanModifier) Description(ctx context.Context) string {
@@ -29,7 +65,7 @@ func (apm *defaultValueAttributePlanModifier) MarkdownDescription(ctx context.Co
 }
 
 //nolint:gocritic,lll // Terraform Framework template code
-func (apm *defaultValueAttributePlanModifier) Modify(_ context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
+func (apm *defaultValueAttributePlanModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
 	if !req.AttributeConfig.IsNull() {
 		return
 	}
@@ -38,5 +74,14 @@ func (apm *defaultValueAttributePlanModifier) Modify(_ context.Context, req tfsd
 		return
 	}
 
+	if !apm.DefaultValue.Type(ctx).Equal(req.AttributePlan.Type(ctx)) {
+		res.Diagnostics.AddAttributeError(
+			req.AttributePath,
+			"Invalid Default Value",
+			fmt.Sprintf("The default value type %s does not match the attribute type %s.", apm.DefaultValue.Type(ctx), req.AttributePlan.Type(ctx)),
+		)
+		return
+	}
+
 	res.AttributePlan = apm.DefaultValue
 }
@@ -0,0 +1,54 @@
+package planmodifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type normalizeDurationAttributePlanModifier struct{}
+
+// NormalizeDuration used to keep the prior value of a duration attribute when the configured one is equivalent (e.g., 5m and 300s).
+// The attribute must be computed, as the planned value may differ from the configured one.
+func NormalizeDuration() tfsdk.AttributePlanModifier {
+	return &normalizeDurationAttributePlanModifier{}
+}
+
+var _ tfsdk.AttributePlanModifier = (*normalizeDurationAttributePlanModifier)(nil)
+
+func (apm *normalizeDurationAttributePlanModifier) Description(ctx context.Context) string {
+	return apm.MarkdownDescription(ctx)
+}
+
+func (apm *normalizeDurationAttributePlanModifier) MarkdownDescription(_ context.Context) string {
+	return "Keeps the prior value if the configured duration is equivalent"
+}
+
+//nolint:gocritic,lll // Terraform Framework template code
+func (apm *normalizeDurationAttributePlanModifier) Modify(_ context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
+	plan, ok := req.AttributePlan.(types.String)
+	if !ok || plan.IsNull() || plan.IsUnknown() {
+		return
+	}
+
+	state, ok := req.AttributeState.(types.String)
+	if !ok || state.IsNull() || state.IsUnknown() {
+		return
+	}
+
+	planned, err := time.ParseDuration(plan.ValueString())
+	if err != nil {
+		return
+	}
+
+	prior, err := time.ParseDuration(state.ValueString())
+	if err != nil {
+		return
+	}
+
+	if planned == prior {
+		res.AttributePlan = state
+	}
+}
@@ -0,0 +1,87 @@
+package liqo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// auditRecord describes a change performed by the provider on a cluster.
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Operation string    `json:"operation"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Duration  string    `json:"duration"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// auditLog appends the audit records to a file, one JSON object per line.
+type auditLog struct {
+	mutex sync.Mutex
+	path  string
+}
+
+func (a *auditLog) append(record *auditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// audit records the outcome of an operation started at the given time on the given object, if the audit file is configured.
+// The error message is redacted, and a failure to write the record is reported as a warning.
+func (d *liqoProviderData) audit(cluster types.String, operation string, obj client.Object, start time.Time,
+	opErr error, redact *redactor) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d.auditLog == nil {
+		return diags
+	}
+
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := apiutil.GVKForObject(obj, d.scheme); err == nil {
+		kind = gvk.Kind
+	}
+
+	record := &auditRecord{
+		Time:      start.UTC(),
+		Cluster:   cluster.ValueString(),
+		Operation: operation,
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Duration:  time.Since(start).String(),
+	}
+	if opErr != nil {
+		record.Error = redact.String(opErr.Error())
+	}
+
+	if err := d.auditLog.append(record); err != nil {
+		diags.AddWarning(
+			"Unable to Write Audit Record",
+			fmt.Sprintf("Failed to append to the audit file %q: %s", d.auditLog.path, err),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,372 @@
+package liqo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planmodifier "github.com/liqotech/terraform-provider-liqo/liqo/attribute_plan_modifier"
+)
+
+const (
+	// liqoControllerManagerName is the name of the Deployment of the Liqo controller manager.
+	liqoControllerManagerName = "liqo-controller-manager"
+	// liqoControllerManagerContainer is the name of the container of the Liqo controller manager.
+	liqoControllerManagerContainer = "controller-manager"
+	// clusterLabelsArg is the argument of the Liqo controller manager configuring the cluster labels.
+	clusterLabelsArg = "--cluster-labels="
+)
+
+var (
+	_ resource.Resource                = &clusterLabelsResource{}
+	_ resource.ResourceWithConfigure   = &clusterLabelsResource{}
+	_ resource.ResourceWithImportState = &clusterLabelsResource{}
+)
+
+// NewClusterLabelsResource provides the initialization of Cluster Labels Resource.
+func NewClusterLabelsResource() resource.Resource {
+	return &clusterLabelsResource{}
+}
+
+type clusterLabelsResource struct {
+	data *liqoProviderData
+}
+
+func (c *clusterLabelsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_labels"
+}
+
+func (c *clusterLabelsResource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		Description: "Manage the labels of the local cluster, advertised to the remote clusters and used to select the virtual nodes. " +
+			"The labels are patched into the arguments of the Liqo controller manager: a helm upgrade of the Liqo chart reverts them " +
+			"to discovery.config.clusterLabels, until the next terraform apply. The labels found at creation are restored on destroy.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+				},
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
+			"labels": {
+				Type:        types.MapType{ElemType: types.StringType},
+				Required:    true,
+				Description: "Labels of the cluster.",
+			},
+			"liqo_namespace": {
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					planmodifier.DefaultString(defaultLiqoNamespace()),
+					resource.RequiresReplace(),
+				},
+				Computed:    true,
+				Description: "Namespace where Liqo is installed. Defaults to LIQO_NAMESPACE, or liqo.",
+			},
+			"previous_labels": {
+				Type:     types.MapType{ElemType: types.StringType},
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Labels configured before the creation of the resource, e.g. through the Liqo chart, restored on destroy.",
+			},
+		},
+	}, nil
+}
+
+// Creation of Cluster Labels Resource to configure the labels of an existing Liqo installation.
+// This resource will reproduce the same effect of the discovery.config.clusterLabels value of the Liqo chart.
+//
+//nolint:gocritic // Terraform Framework template code
+func (c *clusterLabelsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan clusterLabelsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(c.apply(ctx, &plan, "create")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the labels from the configuration of the Liqo controller manager.
+//
+//nolint:gocritic // Terraform Framework template code
+func (c *clusterLabelsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state clusterLabelsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if c.data.deferred {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	ctx, cancel := c.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := c.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Resource",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	if state.LiqoNamespace.IsNull() {
+		state.LiqoNamespace = types.StringValue(defaultLiqoNamespace())
+	}
+
+	CRClient, _, err := c.data.clients(state.Cluster)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Resource",
+			describeError(err),
+		)
+		return
+	}
+
+	var deploy appsv1.Deployment
+	key := client.ObjectKey{Name: liqoControllerManagerName, Namespace: state.LiqoNamespace.ValueString()}
+	if err := CRClient.Get(ctx, key, &deploy); err != nil {
+		if kerrors.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Unable to Read Resource",
+			describeError(err),
+		)
+		return
+	}
+
+	labels := map[string]string{}
+	if container := controllerManagerContainer(&deploy); container != nil {
+		labels = clusterLabelsFromArgs(container.Args)
+	}
+
+	state.Labels, diags = types.MapValueFrom(ctx, types.StringType, labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update changes the labels in place, as they do not affect the established peerings.
+//
+//nolint:gocritic // Terraform Framework template code
+func (c *clusterLabelsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan clusterLabelsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(c.apply(ctx, &plan, "update")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete restores the cluster labels configured in the Liqo controller manager before the creation of the resource.
+//
+//nolint:gocritic // Terraform Framework template code
+func (c *clusterLabelsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data clusterLabelsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Labels = data.PreviousLabels
+	resp.Diagnostics.Append(c.apply(ctx, &data, "delete")...)
+}
+
+// ImportState imports the labels of a Liqo installation, given its namespace.
+func (c *clusterLabelsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importState(ctx, "liqo_namespace", req, resp)
+}
+
+// Configure method to obtain kubernetes Clients provided by provider.
+func (c *clusterLabelsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c.data = req.ProviderData.(*liqoProviderData)
+}
+
+// apply configures the labels of the model in the Liqo controller manager, which is restarted to pick them up.
+func (c *clusterLabelsResource) apply(ctx context.Context, m *clusterLabelsResourceModel, operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	title := "Unable to " + strings.ToUpper(operation[:1]) + operation[1:] + " Resource"
+
+	labels := map[string]string{}
+	if !m.Labels.IsNull() {
+		diags.Append(m.Labels.ElementsAs(ctx, &labels, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	ctx, cancel := c.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := c.data.acquire(ctx)
+	if err != nil {
+		diags.AddError(title, describeError(err))
+		return diags
+	}
+	defer release()
+
+	CRClient, _, err := c.data.clients(m.Cluster)
+	if err != nil {
+		diags.AddError(title, describeError(err))
+		return diags
+	}
+
+	var deploy appsv1.Deployment
+	var previous map[string]string
+	start := time.Now()
+	err = retryOnTransientError(ctx, func() error {
+		key := client.ObjectKey{Name: liqoControllerManagerName, Namespace: m.LiqoNamespace.ValueString()}
+		if err := CRClient.Get(ctx, key, &deploy); err != nil {
+			return err
+		}
+
+		container := controllerManagerContainer(&deploy)
+		if container == nil {
+			return kerrors.NewNotFound(appsv1.Resource("deployments/containers"), liqoControllerManagerContainer)
+		}
+
+		previous = clusterLabelsFromArgs(container.Args)
+		original := deploy.DeepCopy()
+		container.Args = setClusterLabelsArg(container.Args, labels)
+		return CRClient.Patch(ctx, &deploy, client.MergeFrom(original))
+	})
+	diags.Append(c.data.audit(m.Cluster, operation, &deploy, start, err, newRedactor())...)
+	if err != nil {
+		if operation == "delete" && kerrors.IsNotFound(err) {
+			return diags
+		}
+
+		diags.AddError(title, describeError(err))
+		return diags
+	}
+
+	if operation == "create" {
+		// Record the labels set outside of Terraform (e.g. by the Liqo chart), to restore them on destroy.
+		var d diag.Diagnostics
+		m.PreviousLabels, d = types.MapValueFrom(ctx, types.StringType, previous)
+		diags.Append(d...)
+		if len(previous) > 0 {
+			diags.AddWarning(
+				"Cluster Labels Overridden",
+				"The cluster labels already configured in the Liqo controller manager have been replaced, and will be restored on destroy.",
+			)
+		}
+	}
+
+	return diags
+}
+
+// controllerManagerContainer returns the container of the Liqo controller manager within its Deployment,
+// falling back to the first one if no container has the expected name.
+func controllerManagerContainer(deploy *appsv1.Deployment) *corev1.Container {
+	containers := deploy.Spec.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == liqoControllerManagerContainer {
+			return &containers[i]
+		}
+	}
+	if len(containers) > 0 {
+		return &containers[0]
+	}
+	return nil
+}
+
+// clusterLabelsFromArgs parses the cluster labels from the arguments of the Liqo controller manager.
+func clusterLabelsFromArgs(args []string) map[string]string {
+	labels := map[string]string{}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, clusterLabelsArg) {
+			continue
+		}
+
+		for _, pair := range strings.Split(strings.TrimPrefix(arg, clusterLabelsArg), ",") {
+			if key, value, found := strings.Cut(pair, "="); found {
+				labels[key] = value
+			}
+		}
+	}
+	return labels
+}
+
+// setClusterLabelsArg returns the arguments of the Liqo controller manager configured with the given cluster labels.
+func setClusterLabelsArg(args []string, labels map[string]string) []string {
+	result := make([]string, 0, len(args)+1)
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, clusterLabelsArg) {
+			result = append(result, arg)
+		}
+	}
+
+	if len(labels) == 0 {
+		return result
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return append(result, clusterLabelsArg+strings.Join(pairs, ","))
+}
+
+type clusterLabelsResourceModel struct {
+	Cluster        types.String `tfsdk:"cluster"`
+	Labels         types.Map    `tfsdk:"labels"`
+	LiqoNamespace  types.String `tfsdk:"liqo_namespace"`
+	PreviousLabels types.Map    `tfsdk:"previous_labels"`
+}
@@ -0,0 +1,62 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// renamedAttribute describes a string attribute which has been renamed in the schema.
+// During the transition window, both names are accepted and the old one produces a deprecation warning.
+type renamedAttribute struct {
+	OldName string
+	NewName string
+}
+
+// deprecate returns the given attribute marked as deprecated in favor of the new name.
+func (r renamedAttribute) deprecate(attribute tfsdk.Attribute) tfsdk.Attribute {
+	attribute.DeprecationMessage = fmt.Sprintf("Use %s instead, %s will be removed in a future release.", r.NewName, r.OldName)
+	return attribute
+}
+
+// validate checks that the attribute is not configured with both names and, if required, that one of them is set.
+func (r renamedAttribute) validate(ctx context.Context, config tfsdk.Config, required bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var oldValue, newValue types.String
+
+	diags.Append(config.GetAttribute(ctx, path.Root(r.OldName), &oldValue)...)
+	diags.Append(config.GetAttribute(ctx, path.Root(r.NewName), &newValue)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	switch {
+	case !oldValue.IsNull() && !newValue.IsNull():
+		diags.AddAttributeError(
+			path.Root(r.OldName),
+			"Conflicting Attributes",
+			fmt.Sprintf("%s is deprecated and cannot be set together with %s, remove it.", r.OldName, r.NewName),
+		)
+	case required && oldValue.IsNull() && newValue.IsNull():
+		diags.AddAttributeError(
+			path.Root(r.NewName),
+			"Missing Required Attribute",
+			fmt.Sprintf("The argument %s is required.", r.NewName),
+		)
+	}
+
+	return diags
+}
+
+// resolve returns the value of the attribute, reading the new name first and falling back to the old one.
+func (r renamedAttribute) resolve(oldValue, newValue types.String) types.String {
+	if !newValue.IsNull() {
+		return newValue
+	}
+
+	return oldValue
+}
@@ -0,0 +1,109 @@
+package liqo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/transport"
+)
+
+const (
+	// eksTokenPrefix is the prefix of the bearer tokens accepted by the EKS authenticator.
+	eksTokenPrefix = "k8s-aws-v1."
+	// eksClusterIDHeader is the header binding the presigned request to a given EKS cluster.
+	eksClusterIDHeader = "x-k8s-aws-id"
+	// eksTokenRefresh is how often a new token is generated, lower than the 15 minutes validity enforced by EKS.
+	eksTokenRefresh = 10 * time.Minute
+)
+
+// newEKSTokenGenerator returns a function generating EKS authentication tokens, equivalent to "aws eks get-token".
+func newEKSTokenGenerator(conf *awsEKS) (func() (string, error), error) {
+	options := session.Options{
+		Config:            aws.Config{},
+		Profile:           conf.Profile.ValueString(),
+		SharedConfigState: session.SharedConfigEnable,
+	}
+	if conf.Region.ValueString() != "" {
+		options.Config.Region = aws.String(conf.Region.ValueString())
+	}
+
+	sess, err := session.NewSessionWithOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the AWS session: %w", err)
+	}
+
+	stsClient := sts.New(sess)
+	if conf.RoleARN.ValueString() != "" {
+		stsClient = sts.New(sess, &aws.Config{Credentials: stscreds.NewCredentials(sess, conf.RoleARN.ValueString())})
+	}
+
+	clusterName := conf.ClusterName.ValueString()
+	return func() (string, error) {
+		req, _ := stsClient.GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+		req.HTTPRequest.Header.Add(eksClusterIDHeader, clusterName)
+
+		presignedURL, err := req.Presign(60 * time.Second)
+		if err != nil {
+			return "", fmt.Errorf("failed to presign the EKS token request: %w", err)
+		}
+
+		return eksTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presignedURL)), nil
+	}, nil
+}
+
+// eksTokenWrapper returns a transport wrapper authenticating each request with a periodically refreshed EKS token.
+func eksTokenWrapper(generate func() (string, error)) transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &eksTokenRoundTripper{rt: rt, generate: generate}
+	}
+}
+
+type eksTokenRoundTripper struct {
+	rt       http.RoundTripper
+	generate func() (string, error)
+
+	mutex      sync.Mutex
+	token      string
+	expiration time.Time
+}
+
+func (t *eksTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.currentToken()
+	if err != nil {
+		return nil, err
+	}
+
+	req = utilnet.CloneRequest(req)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.rt.RoundTrip(req)
+}
+
+func (t *eksTokenRoundTripper) currentToken() (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token != "" && time.Now().Before(t.expiration) {
+		return t.token, nil
+	}
+
+	token, err := t.generate()
+	if err != nil {
+		return "", err
+	}
+
+	t.token = token
+	t.expiration = time.Now().Add(eksTokenRefresh)
+	return t.token, nil
+}
+
+func (t *eksTokenRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return t.rt
+}
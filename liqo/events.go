@@ -0,0 +1,68 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/reference"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// eventsComponent is the component reported as the source of the events recorded by the provider.
+const eventsComponent = "terraform-provider-liqo"
+
+// recordEvent records an event on the given object, if enabled, to make the changes performed through Terraform visible to operators.
+// A failure to record the event is reported as a warning.
+func (d *liqoProviderData) recordEvent(ctx context.Context, cluster types.String, obj client.Object, reason, message string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !d.config.RecordEvents.ValueBool() {
+		return diags
+	}
+
+	err := func() error {
+		_, KubeClient, err := d.clients(cluster)
+		if err != nil {
+			return err
+		}
+
+		ref, err := reference.GetReference(d.scheme, obj)
+		if err != nil {
+			return err
+		}
+
+		// Events of cluster-scoped objects, such as ForeignClusters, are conventionally stored in the default namespace.
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+
+		now := metav1.Now()
+		event := &corev1.Event{
+			ObjectMeta:          metav1.ObjectMeta{GenerateName: obj.GetName() + ".", Namespace: namespace},
+			InvolvedObject:      *ref,
+			Reason:              reason,
+			Message:             message,
+			Type:                corev1.EventTypeNormal,
+			Source:              corev1.EventSource{Component: eventsComponent},
+			FirstTimestamp:      now,
+			LastTimestamp:       now,
+			Count:               1,
+			ReportingController: eventsComponent,
+		}
+
+		_, err = KubeClient.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{})
+		return err
+	}()
+
+	if err != nil {
+		diags.AddWarning(
+			"Unable to Record Event",
+			fmt.Sprintf("Failed to record the %s event on %s: %s", reason, obj.GetName(), err),
+		)
+	}
+	return diags
+}
@@ -7,12 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/liqotech/liqo/pkg/auth"
-	"github.com/liqotech/liqo/pkg/utils"
-	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
 	planmodifier "github.com/liqotech/terraform-provider-liqo/liqo/attribute_plan_modifier"
 )
 
@@ -27,9 +22,7 @@ func NewGenerateResource() resource.Resource {
 }
 
 type generateResource struct {
-	config     liqoProviderModel
-	CRClient   client.Client
-	KubeClient *kubernetes.Clientset
+	data *liqoProviderData
 }
 
 func (r *generateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -38,8 +31,17 @@ func (r *generateResource) Metadata(_ context.Context, req resource.MetadataRequ
 
 func (r *generateResource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
-		Description: "Generate peering parameters for remote clusters",
+		Description:        "Generate peering parameters for remote clusters",
+		DeprecationMessage: "Use the liqo_peering_parameters data source instead.",
 		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+				},
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
 			"cluster_id": {
 				Type:        types.StringType,
 				Computed:    true,
@@ -58,16 +60,18 @@ func (r *generateResource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diag
 			"local_token": {
 				Type:        types.StringType,
 				Computed:    true,
+				Sensitive:   true,
 				Description: "Provider authentication token.",
 			},
 			"liqo_namespace": {
 				Type:     types.StringType,
 				Optional: true,
 				PlanModifiers: []tfsdk.AttributePlanModifier{
-					planmodifier.DefaultValue(types.StringValue("liqo")),
+					planmodifier.DefaultString(defaultLiqoNamespace()),
+					resource.RequiresReplace(),
 				},
 				Computed:    true,
-				Description: "Namespace where is Liqo installed in provider cluster.",
+				Description: "Namespace where Liqo is installed in provider cluster. Defaults to LIQO_NAMESPACE, or liqo.",
 			},
 		},
 	}, nil
@@ -85,62 +89,41 @@ func (r *generateResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	overrides, loader, err := CheckParameters(&r.config)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
-		return
-	}
-
-	CRClient, KubeClient, err := NewClients(overrides, loader)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
-		return
-	}
+	ctx, cancel := r.data.withTimeout(ctx)
+	defer cancel()
 
-	r.CRClient = CRClient
-	r.KubeClient = KubeClient
-
-	clusterIdentity, err := utils.GetClusterIdentityWithControllerClient(ctx, r.CRClient, plan.LiqoNamespace.ValueString())
+	release, err := r.data.acquire(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
+	defer release()
 
-	localToken, err := auth.GetToken(ctx, r.CRClient, plan.LiqoNamespace.ValueString())
+	CRClient, _, err := r.data.clients(plan.Cluster)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
 
-	authEP, err := foreigncluster.GetHomeAuthURL(ctx, r.CRClient, plan.LiqoNamespace.ValueString())
+	params, err := getPeeringParameters(ctx, CRClient, plan.LiqoNamespace.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
 
-	if clusterIdentity.ClusterName == "" {
-		clusterIdentity.ClusterName = clusterIdentity.ClusterID
-	}
-
-	plan.ClusterID = types.StringValue(clusterIdentity.ClusterID)
-	plan.ClusterName = types.StringValue(clusterIdentity.ClusterName)
-	plan.LocalToken = types.StringValue(localToken)
-	plan.AuthEP = types.StringValue(authEP)
+	plan.ClusterID = types.StringValue(params.ClusterID)
+	plan.ClusterName = types.StringValue(params.ClusterName)
+	plan.LocalToken = types.StringValue(params.Token)
+	plan.AuthEP = types.StringValue(params.AuthEP)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -149,6 +132,8 @@ func (r *generateResource) Create(ctx context.Context, req resource.CreateReques
 	}
 }
 
+// Read refreshes the pairing parameters, so that rotated tokens and endpoints are propagated to dependent resources.
+//
 //nolint:gocritic // Terraform Framework template code
 func (r *generateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state generateResourceModel
@@ -158,6 +143,48 @@ func (r *generateResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	// The parameters cannot be refreshed until the provider configuration is known, hence the last ones are kept.
+	if r.data.deferred {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	ctx, cancel := r.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := r.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Resource",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	CRClient, _, err := r.data.clients(state.Cluster)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Resource",
+			describeError(err),
+		)
+		return
+	}
+
+	params, err := getPeeringParameters(ctx, CRClient, state.LiqoNamespace.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Resource",
+			describeError(err),
+		)
+		return
+	}
+
+	state.ClusterID = types.StringValue(params.ClusterID)
+	state.ClusterName = types.StringValue(params.ClusterName)
+	state.LocalToken = types.StringValue(params.Token)
+	state.AuthEP = types.StringValue(params.AuthEP)
+
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -165,6 +192,8 @@ func (r *generateResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 }
 
+// Update is never invoked, since every configurable attribute requires the replacement of the resource.
+//
 //nolint:gocritic // Terraform Framework template code
 func (r *generateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	resp.Diagnostics.AddError(
@@ -173,6 +202,8 @@ func (r *generateResource) Update(ctx context.Context, req resource.UpdateReques
 	)
 }
 
+// Delete only removes the resource from the Terraform state, as no object is created in the cluster.
+//
 //nolint:gocritic // Terraform Framework template code
 func (r *generateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 }
@@ -183,10 +214,11 @@ func (r *generateResource) Configure(_ context.Context, req resource.ConfigureRe
 		return
 	}
 
-	r.config = req.ProviderData.(liqoProviderModel)
+	r.data = req.ProviderData.(*liqoProviderData)
 }
 
 type generateResourceModel struct {
+	Cluster       types.String `tfsdk:"cluster"`
 	ClusterID     types.String `tfsdk:"cluster_id"`
 	ClusterName   types.String `tfsdk:"cluster_name"`
 	AuthEP        types.String `tfsdk:"auth_ep"`
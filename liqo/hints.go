@@ -0,0 +1,52 @@
+package liqo
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// troubleshootingHint returns a remediation hint for the most common failures, or an empty string if none applies.
+func troubleshootingHint(err error) string {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var phaseErr *peeringPhaseError
+
+	switch {
+	case errors.As(err, &phaseErr) && phaseErr.phase == "network":
+		return "The network tunnel between the clusters has not been established: check that the liqo-gateway service of both clusters " +
+			"got an external address, as a LoadBalancer stuck in Pending requires a load balancer implementation or the NodePort service type, " +
+			"and that the gateways can reach each other on the UDP tunnel port."
+	case errors.Is(err, errConnectionDeferred):
+		return "Terraform cannot return unknown data source results while planning: add a depends_on on the resources providing the connection, " +
+			"so that the read is deferred to the apply."
+	case kerrors.IsUnauthorized(err):
+		return "The cluster rejected the credentials: check the kubernetes settings of the provider, and that the token or certificate is not expired."
+	case kerrors.IsForbidden(err):
+		return "The configured identity is not allowed to perform the operation: check its RBAC permissions on the Liqo resources, secrets and config maps."
+	case meta.IsNoMatchError(err):
+		return "The Liqo CRDs are not installed in the cluster: install Liqo, or check that the provider targets the right cluster."
+	case kerrors.IsNotFound(err) && strings.Contains(err.Error(), "configmaps"):
+		return "The Liqo cluster identity has not been found: check that Liqo is installed in the namespace set with liqo_namespace."
+	case isTransientError(err):
+		return "The Liqo webhooks are not ready yet, as it happens right after the installation: retry once the Liqo pods are running."
+	case errors.As(err, &dnsErr), strings.Contains(err.Error(), "connection refused"):
+		return "The API server is not reachable: check the host of the cluster and the network connectivity."
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "The API server did not reply in time: check the network connectivity, or increase request_timeout and operation_timeout."
+	}
+
+	return ""
+}
+
+// describeError returns the message of the given error, followed by the troubleshooting hint if any.
+func describeError(err error) string {
+	if hint := troubleshootingHint(err); hint != "" {
+		return err.Error() + "\n\nHint: " + hint
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,33 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// importState initializes the state of an imported resource from an identifier of the form [<cluster>/]<value>,
+// where value is stored in the given attribute, and cluster selects an entry of the provider clusters.
+// The remaining attributes are retrieved from the cluster by Read.
+func importState(ctx context.Context, attribute string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cluster, value := "", req.ID
+	if i := strings.LastIndex(req.ID, "/"); i >= 0 {
+		cluster, value = req.ID[:i], req.ID[i+1:]
+	}
+
+	if value == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("Expected an identifier in the form [<cluster>/]<%s>, got %q.", attribute, req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(attribute), value)...)
+	if cluster != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster"), cluster)...)
+	}
+}
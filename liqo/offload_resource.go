@@ -2,13 +2,20 @@ package liqo
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -18,8 +25,10 @@ import (
 )
 
 var (
-	_ resource.Resource              = &offloadResource{}
-	_ resource.ResourceWithConfigure = &offloadResource{}
+	_ resource.Resource                = &offloadResource{}
+	_ resource.ResourceWithConfigure   = &offloadResource{}
+	_ resource.ResourceWithImportState = &offloadResource{}
+	_ resource.ResourceWithModifyPlan  = &offloadResource{}
 )
 
 // NewOffloadResource provides the initialization of Offload Resource.
@@ -28,7 +37,7 @@ func NewOffloadResource() resource.Resource {
 }
 
 type offloadResource struct {
-	config liqoProviderModel
+	data *liqoProviderData
 }
 
 func (o *offloadResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -39,35 +48,73 @@ func (o *offloadResource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagn
 	return tfsdk.Schema{
 		Description: "Offload a namespace.",
 		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+				},
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
 			"namespace": {
-				Type:        types.StringType,
-				Required:    true,
+				Type:     types.StringType,
+				Required: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+				},
 				Description: "Offload a namespace.",
 			},
 			"pod_offloading_strategy": {
 				Type:     types.StringType,
 				Optional: true,
 				PlanModifiers: []tfsdk.AttributePlanModifier{
-					planmodifier.DefaultValue(types.StringValue("LocalAndRemote")),
+					planmodifier.DefaultString("LocalAndRemote"),
+					planmodifier.CaseInsensitive(),
 				},
 				Computed:    true,
-				Description: "Namespace to offload.",
+				Description: "High-level constraints with respect to the pod offloading strategy (e.g., _remote_ vs _local_).",
 			},
 			"namespace_mapping_strategy": {
 				Type:     types.StringType,
 				Optional: true,
 				PlanModifiers: []tfsdk.AttributePlanModifier{
-					planmodifier.DefaultValue(types.StringValue("DefaultName")),
+					planmodifier.DefaultString("DefaultName"),
+					planmodifier.CaseInsensitive(),
+					resource.RequiresReplace(),
 				},
 				Computed:    true,
-				Description: "Naming strategy used to create the remote namespace.",
+				Description: "Naming strategy used to create the remote namespace. Changing it requires the offloading to be recreated.",
+			},
+			"check_namespace": {
+				Type:     types.BoolType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					planmodifier.DefaultBool(false),
+				},
+				Computed: true,
+				Description: "Whether to check that the namespace exists and is not being deleted, warning about it at plan time " +
+					"and failing with a precise error before the offloading is created.",
+			},
+			"selected_virtual_nodes": {
+				Type:        types.ListType{ElemType: types.StringType},
+				Computed:    true,
+				Description: "Virtual nodes currently matching the cluster selector, where pods of the namespace can be scheduled.",
+			},
+			"selected_cluster_ids": {
+				Type:        types.ListType{ElemType: types.StringType},
+				Computed:    true,
+				Description: "IDs of the remote clusters currently matching the cluster selector.",
+			},
+			"remote_clusters_status": {
+				Type:        types.MapType{ElemType: types.StringType},
+				Computed:    true,
+				Description: "Offloading status of the namespace in each remote cluster, keyed by cluster ID (Ready, NotReady or NotRequired).",
 			},
 			"cluster_selector_terms": {
 				Optional: true,
 				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
 					"match_expressions": {
 						Optional: true,
-						Computed: true,
 						Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
 							"key": {
 								Type:        types.StringType,
@@ -107,112 +154,102 @@ func (o *offloadResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	overrides, loader, err := CheckParameters(&o.config)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
+	resp.Diagnostics.Append(o.apply(ctx, &plan, "create")...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	CRClient, _, err := NewClients(overrides, loader)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+}
 
-	var clusterSelector [][]metav1.LabelSelectorRequirement
+// Read refreshes the offloading from its NamespaceOffloading, so that changes made out-of-band show up as drift,
+// and removes the resource from the state if the NamespaceOffloading or the namespace have been deleted.
+//
+//nolint:gocritic // Terraform Framework template code
+func (o *offloadResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state offloadResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	for _, selector := range plan.ClusterSelectorTerms {
-		s := &metav1.LabelSelector{
-			MatchLabels:      map[string]string{},
-			MatchExpressions: []metav1.LabelSelectorRequirement{},
+	if !o.data.deferred {
+		ctx, cancel := o.data.withTimeout(ctx)
+		defer cancel()
+
+		release, err := o.data.acquire(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Resource",
+				describeError(err),
+			)
+			return
 		}
-
-		for _, matchExpression := range selector.MatchExpressions {
-			var values []string
-
-			for _, value := range matchExpression.Values {
-				values = append(values, value.ValueString())
-			}
-			req := metav1.LabelSelectorRequirement{
-				Key:      matchExpression.Key.ValueString(),
-				Operator: metav1.LabelSelectorOperator(matchExpression.Operator.ValueString()),
-				Values:   values,
-			}
-			s.MatchExpressions = append(s.MatchExpressions, req)
+		defer release()
+
+		CRClient, _, err := o.data.clients(state.Cluster)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Resource",
+				describeError(err),
+			)
+			return
 		}
 
-		clusterSelector = append(clusterSelector, s.MatchExpressions)
-	}
-
-	terms := []corev1.NodeSelectorTerm{}
-
-	for _, selector := range clusterSelector {
-		var requirements []corev1.NodeSelectorRequirement
-
-		for _, r := range selector {
-			requirements = append(requirements, corev1.NodeSelectorRequirement{
-				Key:      r.Key,
-				Operator: corev1.NodeSelectorOperator(r.Operator),
-				Values:   r.Values,
-			})
+		found, err := state.refreshFromCluster(ctx, CRClient)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Resource",
+				describeError(err),
+			)
+			return
+		}
+		if !found {
+			resp.State.RemoveResource(ctx)
+			return
 		}
 
-		terms = append(terms, corev1.NodeSelectorTerm{MatchExpressions: requirements})
-	}
-
-	nsoff := &offloadingv1alpha1.NamespaceOffloading{ObjectMeta: metav1.ObjectMeta{
-		Name: consts.DefaultNamespaceOffloadingName, Namespace: plan.Namespace.ValueString()}}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, CRClient, nsoff, func() error {
-		nsoff.Spec.PodOffloadingStrategy = offloadingv1alpha1.PodOffloadingStrategyType(plan.PodOffloadingStrategy.ValueString())
-		nsoff.Spec.NamespaceMappingStrategy = offloadingv1alpha1.NamespaceMappingStrategyType(plan.NamespaceMappingStrategy.ValueString())
-		nsoff.Spec.ClusterSelector = corev1.NodeSelector{NodeSelectorTerms: terms}
-		return nil
-	})
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
-
-		return
+		resp.Diagnostics.Append(state.setSelectedVirtualNodes(ctx, CRClient)...)
+		resp.Diagnostics.Append(state.setRemoteClustersStatus(ctx, CRClient)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	diags = resp.State.Set(ctx, plan)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
 
+// Update applies the changes of the pod offloading strategy and of the cluster selector to the NamespaceOffloading.
+// The namespace and the namespace mapping strategy cannot be changed in place, and require a replacement.
+//
 //nolint:gocritic // Terraform Framework template code
-func (o *offloadResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state offloadResourceModel
-	diags := req.State.Get(ctx, &state)
+func (o *offloadResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan offloadResourceModel
+	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(o.apply(ctx, &plan, "update")...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-}
 
-//nolint:gocritic // Terraform Framework template code
-func (o *offloadResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError(
-		"Unable to Update Resource",
-		"Update is not supported/permitted yet.",
-	)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 }
 
 //nolint:gocritic // Terraform Framework template code
@@ -220,33 +257,102 @@ func (o *offloadResource) Delete(ctx context.Context, req resource.DeleteRequest
 	var data offloadResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
-	overrides, loader, err := CheckParameters(&o.config)
+	ctx, cancel := o.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := o.data.acquire(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Delete Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
+	defer release()
 
-	CRClient, _, err := NewClients(overrides, loader)
+	CRClient, _, err := o.data.clients(data.Cluster)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Delete Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
 
 	nsoff := &offloadingv1alpha1.NamespaceOffloading{ObjectMeta: metav1.ObjectMeta{
 		Name: consts.DefaultNamespaceOffloadingName, Namespace: data.Namespace.ValueString()}}
-	if err := CRClient.Delete(ctx, nsoff); client.IgnoreNotFound(err) != nil {
+	start := time.Now()
+	err = retryOnTransientError(ctx, func() error { return CRClient.Delete(ctx, nsoff) })
+	resp.Diagnostics.Append(o.data.audit(data.Cluster, "delete", nsoff, start, client.IgnoreNotFound(err), newRedactor())...)
+	if client.IgnoreNotFound(err) != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Delete Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
+
+	if err == nil {
+		resp.Diagnostics.Append(o.data.recordEvent(ctx, data.Cluster, nsoff, "TerraformDelete", "Namespace offloading removed by Terraform")...)
+	}
+}
+
+// ImportState imports an existing offloading, given the offloaded namespace.
+func (o *offloadResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importState(ctx, "namespace", req, resp)
+}
+
+// ModifyPlan warns at plan time if the namespace to offload does not exist or is being deleted, when check_namespace is set.
+// A warning is reported rather than an error, as the namespace may be created in the same apply.
+func (o *offloadResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || o.data == nil || o.data.deferred {
+		return
+	}
+
+	var check types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("check_namespace"), &check)...)
+	if resp.Diagnostics.HasError() || !check.ValueBool() {
+		return
+	}
+
+	var namespace, cluster types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("namespace"), &namespace)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cluster"), &cluster)...)
+	if resp.Diagnostics.HasError() || namespace.IsUnknown() || cluster.IsUnknown() {
+		return
+	}
+
+	ctx, cancel := o.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := o.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("namespace"),
+			"Unable to Check Namespace",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	CRClient, _, err := o.data.clients(cluster)
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("namespace"),
+			"Unable to Check Namespace",
+			describeError(err),
+		)
+		return
+	}
+
+	if err := checkNamespace(ctx, CRClient, namespace.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("namespace"),
+			"Namespace Not Available",
+			describeError(err)+"\n\nThe offloading will fail unless the namespace is available when it is applied.",
+		)
+	}
 }
 
 // Configure method to obtain kubernetes Clients provided by provider.
@@ -255,7 +361,66 @@ func (o *offloadResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	o.config = req.ProviderData.(liqoProviderModel)
+	o.data = req.ProviderData.(*liqoProviderData)
+}
+
+// apply configures the NamespaceOffloading of the model, and sets the resulting offloading status.
+// Failures in retrieving the status are reported as warnings, so that the configured offloading is saved in the state.
+func (o *offloadResource) apply(ctx context.Context, m *offloadResourceModel, operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	title := "Unable to " + strings.ToUpper(operation[:1]) + operation[1:] + " Resource"
+
+	ctx, cancel := o.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := o.data.acquire(ctx)
+	if err != nil {
+		diags.AddError(title, describeError(err))
+		return diags
+	}
+	defer release()
+
+	CRClient, _, err := o.data.clients(m.Cluster)
+	if err != nil {
+		diags.AddError(title, describeError(err))
+		return diags
+	}
+
+	if operation == "create" && m.CheckNamespace.ValueBool() {
+		if err := checkNamespace(ctx, CRClient, m.Namespace.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("namespace"), title, describeError(err))
+			return diags
+		}
+	}
+
+	terms := m.nodeSelectorTerms()
+
+	nsoff := &offloadingv1alpha1.NamespaceOffloading{ObjectMeta: metav1.ObjectMeta{
+		Name: consts.DefaultNamespaceOffloadingName, Namespace: m.Namespace.ValueString()}}
+
+	start := time.Now()
+	err = retryOnTransientError(ctx, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, CRClient, nsoff, func() error {
+			o.data.applyDefaultMetadata(nsoff)
+			m.setSpec(nsoff, terms)
+			return nil
+		})
+		return err
+	})
+	diags.Append(o.data.audit(m.Cluster, operation, nsoff, start, err, newRedactor())...)
+	if err != nil {
+		diags.AddError(title, describeError(err))
+		return diags
+	}
+
+	if operation == "create" {
+		diags.Append(o.data.recordEvent(ctx, m.Cluster, nsoff, "TerraformCreate", "Namespace offloading configured by Terraform")...)
+	} else {
+		diags.Append(o.data.recordEvent(ctx, m.Cluster, nsoff, "TerraformUpdate", "Namespace offloading updated by Terraform")...)
+	}
+
+	diags.Append(m.setStatus(ctx, CRClient)...)
+	return diags
 }
 
 type matchExpression struct {
@@ -269,8 +434,287 @@ type matchExpressions struct {
 }
 
 type offloadResourceModel struct {
+	Cluster                  types.String       `tfsdk:"cluster"`
 	Namespace                types.String       `tfsdk:"namespace"`
 	PodOffloadingStrategy    types.String       `tfsdk:"pod_offloading_strategy"`
 	NamespaceMappingStrategy types.String       `tfsdk:"namespace_mapping_strategy"`
+	CheckNamespace           types.Bool         `tfsdk:"check_namespace"`
 	ClusterSelectorTerms     []matchExpressions `tfsdk:"cluster_selector_terms"`
+	SelectedVirtualNodes     types.List         `tfsdk:"selected_virtual_nodes"`
+	SelectedClusterIDs       types.List         `tfsdk:"selected_cluster_ids"`
+	RemoteClustersStatus     types.Map          `tfsdk:"remote_clusters_status"`
+}
+
+// nodeSelectorTerms returns the cluster selector terms as configured in the NamespaceOffloading.
+func (m *offloadResourceModel) nodeSelectorTerms() []corev1.NodeSelectorTerm {
+	var clusterSelector [][]metav1.LabelSelectorRequirement
+
+	for _, selector := range m.ClusterSelectorTerms {
+		s := &metav1.LabelSelector{
+			MatchLabels:      map[string]string{},
+			MatchExpressions: []metav1.LabelSelectorRequirement{},
+		}
+
+		for _, matchExpression := range selector.MatchExpressions {
+			var values []string
+
+			for _, value := range matchExpression.Values {
+				values = append(values, value.ValueString())
+			}
+			req := metav1.LabelSelectorRequirement{
+				Key:      matchExpression.Key.ValueString(),
+				Operator: metav1.LabelSelectorOperator(matchExpression.Operator.ValueString()),
+				Values:   values,
+			}
+			s.MatchExpressions = append(s.MatchExpressions, req)
+		}
+
+		clusterSelector = append(clusterSelector, s.MatchExpressions)
+	}
+
+	terms := []corev1.NodeSelectorTerm{}
+
+	for _, selector := range clusterSelector {
+		var requirements []corev1.NodeSelectorRequirement
+
+		for _, r := range selector {
+			requirements = append(requirements, corev1.NodeSelectorRequirement{
+				Key:      r.Key,
+				Operator: corev1.NodeSelectorOperator(r.Operator),
+				Values:   r.Values,
+			})
+		}
+
+		terms = append(terms, corev1.NodeSelectorTerm{MatchExpressions: requirements})
+	}
+
+	return terms
+}
+
+// setStatus refreshes the computed status of an offloading just written to the cluster. Failures are reported as warnings,
+// and leave the status empty, as the state shall be saved anyway to keep track of the NamespaceOffloading.
+func (m *offloadResourceModel) setStatus(ctx context.Context, cl client.Client) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, d := range append(m.setSelectedVirtualNodes(ctx, cl), m.setRemoteClustersStatus(ctx, cl)...) {
+		if d.Severity() == diag.SeverityError {
+			diags.AddWarning(d.Summary(), d.Detail())
+			continue
+		}
+		diags.Append(d)
+	}
+
+	if m.SelectedVirtualNodes.IsUnknown() {
+		m.SelectedVirtualNodes = types.ListNull(types.StringType)
+	}
+	if m.SelectedClusterIDs.IsUnknown() {
+		m.SelectedClusterIDs = types.ListNull(types.StringType)
+	}
+	if m.RemoteClustersStatus.IsUnknown() {
+		m.RemoteClustersStatus = types.MapNull(types.StringType)
+	}
+	return diags
+}
+
+// setSelectedVirtualNodes evaluates the cluster selector against the virtual nodes, and stores the matching ones.
+// As for Kubernetes node selectors, a node matches if it satisfies any of the terms, and an empty selector matches all nodes.
+func (m *offloadResourceModel) setSelectedVirtualNodes(ctx context.Context, cl client.Client) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var nodes corev1.NodeList
+	if err := cl.List(ctx, &nodes, client.MatchingLabels{consts.TypeLabel: consts.TypeNode}); err != nil {
+		diags.AddError("Unable to Retrieve Virtual Nodes", describeError(err))
+		return diags
+	}
+
+	terms := m.nodeSelectorTerms()
+	var selectors []labels.Selector
+	for i := range terms {
+		// Terms without expressions match no nodes.
+		if len(terms[i].MatchExpressions) == 0 {
+			continue
+		}
+
+		selector, err := nodeSelectorRequirementsAsSelector(terms[i].MatchExpressions)
+		if err != nil {
+			diags.AddError("Invalid Cluster Selector", err.Error())
+			return diags
+		}
+		selectors = append(selectors, selector)
+	}
+
+	nodeNames, clusterIDs := []string{}, []string{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		selected := len(terms) == 0
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(node.Labels)) {
+				selected = true
+				break
+			}
+		}
+
+		if selected {
+			nodeNames = append(nodeNames, node.Name)
+			clusterIDs = append(clusterIDs, node.Labels[consts.RemoteClusterID])
+		}
+	}
+
+	var d diag.Diagnostics
+	m.SelectedVirtualNodes, d = types.ListValueFrom(ctx, types.StringType, nodeNames)
+	diags.Append(d...)
+	m.SelectedClusterIDs, d = types.ListValueFrom(ctx, types.StringType, clusterIDs)
+	diags.Append(d...)
+	return diags
+}
+
+// setRemoteClustersStatus stores the offloading status of the namespace in each remote cluster, as reported by Liqo.
+func (m *offloadResourceModel) setRemoteClustersStatus(ctx context.Context, cl client.Client) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var nsoff offloadingv1alpha1.NamespaceOffloading
+	key := client.ObjectKey{Name: consts.DefaultNamespaceOffloadingName, Namespace: m.Namespace.ValueString()}
+	if err := cl.Get(ctx, key, &nsoff); client.IgnoreNotFound(err) != nil {
+		diags.AddError("Unable to Retrieve Offloading Status", describeError(err))
+		return diags
+	}
+
+	status := map[string]string{}
+	for clusterID, conditions := range nsoff.Status.RemoteNamespacesConditions {
+		status[clusterID] = "NotReady"
+		for i := range conditions {
+			switch {
+			case conditions[i].Type == offloadingv1alpha1.NamespaceOffloadingRequired && conditions[i].Status == corev1.ConditionFalse:
+				status[clusterID] = "NotRequired"
+			case conditions[i].Type == offloadingv1alpha1.NamespaceReady && conditions[i].Status == corev1.ConditionTrue:
+				if status[clusterID] != "NotRequired" {
+					status[clusterID] = "Ready"
+				}
+			}
+		}
+	}
+
+	var d diag.Diagnostics
+	m.RemoteClustersStatus, d = types.MapValueFrom(ctx, types.StringType, status)
+	diags.Append(d...)
+	return diags
+}
+
+// nodeSelectorOperators maps the operators of the node selectors to the ones of the label selectors.
+// Unlike metav1.LabelSelectorAsSelector, this supports the Gt and Lt operators, which are valid in node selectors only.
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// nodeSelectorRequirementsAsSelector converts the expressions of a node selector term to a label selector,
+// matching the nodes the same way the scheduler does.
+func nodeSelectorRequirementsAsSelector(expressions []corev1.NodeSelectorRequirement) (labels.Selector, error) {
+	selector := labels.NewSelector()
+	for _, expression := range expressions {
+		operator, found := nodeSelectorOperators[expression.Operator]
+		if !found {
+			return nil, fmt.Errorf("%q is not a valid node selector operator", expression.Operator)
+		}
+
+		requirement, err := labels.NewRequirement(expression.Key, operator, expression.Values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*requirement)
+	}
+	return selector, nil
+}
+
+// setSpec sets the spec of the NamespaceOffloading from the model, sending the canonical spelling of the strategies.
+func (m *offloadResourceModel) setSpec(nsoff *offloadingv1alpha1.NamespaceOffloading, terms []corev1.NodeSelectorTerm) {
+	nsoff.Spec.PodOffloadingStrategy = offloadingv1alpha1.PodOffloadingStrategyType(
+		canonical(m.PodOffloadingStrategy.ValueString(), podOffloadingStrategies...))
+	nsoff.Spec.NamespaceMappingStrategy = offloadingv1alpha1.NamespaceMappingStrategyType(
+		canonical(m.NamespaceMappingStrategy.ValueString(), namespaceMappingStrategies...))
+	nsoff.Spec.ClusterSelector = corev1.NodeSelector{NodeSelectorTerms: terms}
+}
+
+// refreshFromCluster fills the strategies and the cluster selector of the state from the NamespaceOffloading.
+// It returns false if the NamespaceOffloading no longer exists, or its namespace is being deleted.
+func (m *offloadResourceModel) refreshFromCluster(ctx context.Context, cl client.Client) (bool, error) {
+	var ns corev1.Namespace
+	if err := cl.Get(ctx, client.ObjectKey{Name: m.Namespace.ValueString()}, &ns); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if ns.DeletionTimestamp != nil || ns.Status.Phase == corev1.NamespaceTerminating {
+		return false, nil
+	}
+
+	var nsoff offloadingv1alpha1.NamespaceOffloading
+	key := client.ObjectKey{Name: consts.DefaultNamespaceOffloadingName, Namespace: m.Namespace.ValueString()}
+	if err := cl.Get(ctx, key, &nsoff); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// The strategies are matched ignoring the case, so that the configured spelling is not reported as drift.
+	if !strings.EqualFold(m.PodOffloadingStrategy.ValueString(), string(nsoff.Spec.PodOffloadingStrategy)) {
+		m.PodOffloadingStrategy = types.StringValue(string(nsoff.Spec.PodOffloadingStrategy))
+	}
+	if !strings.EqualFold(m.NamespaceMappingStrategy.ValueString(), string(nsoff.Spec.NamespaceMappingStrategy)) {
+		m.NamespaceMappingStrategy = types.StringValue(string(nsoff.Spec.NamespaceMappingStrategy))
+	}
+
+	m.ClusterSelectorTerms = nil
+	for _, term := range nsoff.Spec.ClusterSelector.NodeSelectorTerms {
+		var expressions matchExpressions
+		for _, r := range term.MatchExpressions {
+			expression := matchExpression{Key: types.StringValue(r.Key), Operator: types.StringValue(string(r.Operator))}
+			for _, value := range r.Values {
+				expression.Values = append(expression.Values, types.StringValue(value))
+			}
+			expressions.MatchExpressions = append(expressions.MatchExpressions, expression)
+		}
+		m.ClusterSelectorTerms = append(m.ClusterSelectorTerms, expressions)
+	}
+
+	return true, nil
+}
+
+// Accepted values of the offloading strategies, which can be configured with any case.
+var (
+	podOffloadingStrategies    = []string{"LocalAndRemote", "Local", "Remote"}
+	namespaceMappingStrategies = []string{"DefaultName", "EnforceSameName"}
+)
+
+// canonical returns the spelling of the given value among the accepted ones, ignoring the case.
+// Unknown values are returned as is, to be rejected by Liqo.
+func canonical(value string, values ...string) string {
+	for _, v := range values {
+		if strings.EqualFold(value, v) {
+			return v
+		}
+	}
+	return value
+}
+
+// checkNamespace verifies that the namespace to offload exists and is not being deleted.
+func checkNamespace(ctx context.Context, cl client.Client, namespace string) error {
+	var ns corev1.Namespace
+	if err := cl.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("namespace %q does not exist", namespace)
+		}
+		return err
+	}
+
+	if ns.DeletionTimestamp != nil || ns.Status.Phase == corev1.NamespaceTerminating {
+		return fmt.Errorf("namespace %q is being deleted", namespace)
+	}
+	return nil
 }
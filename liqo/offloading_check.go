@@ -0,0 +1,143 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+const (
+	// offloadingCheckInterval is the interval between the checks of the offloading smoke test.
+	offloadingCheckInterval = 2 * time.Second
+	// offloadingCheckImage is the image of the pod scheduled by the offloading smoke test.
+	offloadingCheckImage = "registry.k8s.io/pause:3.9"
+	// offloadingCheckTimeout is the maximum duration of the offloading smoke test, including the wait for the peering.
+	offloadingCheckTimeout = 5 * time.Minute
+)
+
+// waitForOutgoingPeering waits until the outgoing peering towards the given cluster is established,
+// and the corresponding virtual node is ready.
+func waitForOutgoingPeering(ctx context.Context, cl client.Client, clusterID string) error {
+	return wait.PollUntilContextCancel(ctx, offloadingCheckInterval, true, func(ctx context.Context) (bool, error) {
+		fc, err := foreigncluster.GetForeignClusterByID(ctx, cl, clusterID)
+		if err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		if !foreigncluster.IsOutgoingJoined(fc) {
+			return false, nil
+		}
+
+		var nodes corev1.NodeList
+		if err := cl.List(ctx, &nodes, client.MatchingLabels{consts.TypeLabel: consts.TypeNode, consts.RemoteClusterID: clusterID}); err != nil {
+			return false, err
+		}
+		for i := range nodes.Items {
+			for _, condition := range nodes.Items[i].Status.Conditions {
+				if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+}
+
+// verifyOffloading checks that the peering towards the given cluster is usable, scheduling a short-lived pod
+// onto the virtual node in a temporary namespace offloaded to that cluster only, and waiting for it to run.
+// The temporary namespace is deleted once the check completes, whatever its outcome.
+func verifyOffloading(ctx context.Context, cl client.Client, clusterID string) error {
+	ctx, cancel := context.WithTimeout(ctx, offloadingCheckTimeout)
+	defer cancel()
+
+	if err := waitForOutgoingPeering(ctx, cl, clusterID); err != nil {
+		return fmt.Errorf("waiting for the peering with cluster %q to be established: %w", clusterID, err)
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "liqo-offloading-check-"}}
+	if err := cl.Create(ctx, namespace); err != nil {
+		return err
+	}
+	defer func() {
+		// The cleanup is performed even if the check timed out.
+		_ = client.IgnoreNotFound(cl.Delete(context.WithoutCancel(ctx), namespace))
+	}()
+
+	nsoff := &offloadingv1alpha1.NamespaceOffloading{
+		ObjectMeta: metav1.ObjectMeta{Name: consts.DefaultNamespaceOffloadingName, Namespace: namespace.Name},
+		Spec: offloadingv1alpha1.NamespaceOffloadingSpec{
+			NamespaceMappingStrategy: offloadingv1alpha1.DefaultNameMappingStrategyType,
+			PodOffloadingStrategy:    offloadingv1alpha1.RemotePodOffloadingStrategyType,
+			ClusterSelector: corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      consts.RemoteClusterID,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{clusterID},
+				}},
+			}}},
+		},
+	}
+	if err := cl.Create(ctx, nsoff); err != nil {
+		return err
+	}
+
+	// The pod is rejected by the Liqo webhook until the remote namespace is created.
+	err := wait.PollUntilContextCancel(ctx, offloadingCheckInterval, true, func(ctx context.Context) (bool, error) {
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(nsoff), nsoff); err != nil {
+			return false, err
+		}
+		return nsoff.Status.OffloadingPhase == offloadingv1alpha1.ReadyOffloadingPhaseType, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for the namespace to be offloaded to cluster %q (phase %q): %w",
+			clusterID, nsoff.Status.OffloadingPhase, err)
+	}
+
+	// The toleration for the virtual node taint is added by the Liqo webhook, as the namespace is offloaded.
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "offloading-check", Namespace: namespace.Name},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{consts.RemoteClusterID: clusterID},
+			Containers:   []corev1.Container{{Name: "check", Image: offloadingCheckImage}},
+		},
+	}
+	err = retryOnTransientError(ctx, func() error {
+		err := cl.Create(ctx, pod)
+		if kerrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollUntilContextCancel(ctx, offloadingCheckInterval, true, func(ctx context.Context) (bool, error) {
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+			return false, err
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodRunning, corev1.PodSucceeded:
+			return true, nil
+		case corev1.PodFailed:
+			return false, fmt.Errorf("the test pod failed: %s", pod.Status.Message)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for the test pod to run on cluster %q: %w", clusterID, err)
+	}
+
+	return nil
+}
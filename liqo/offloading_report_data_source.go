@@ -0,0 +1,275 @@
+package liqo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	virtualkubeletv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var (
+	_ datasource.DataSource              = &offloadingReportDataSource{}
+	_ datasource.DataSourceWithConfigure = &offloadingReportDataSource{}
+)
+
+// NewOffloadingReportDataSource provides the initialization of Offloading Report Data Source.
+func NewOffloadingReportDataSource() datasource.DataSource {
+	return &offloadingReportDataSource{}
+}
+
+type offloadingReportDataSource struct {
+	data *liqoProviderData
+}
+
+func (d *offloadingReportDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_offloading_report"
+}
+
+func (d *offloadingReportDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		Description: "Report the offloaded namespaces of the cluster, with the pods running in each remote cluster.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
+			"namespaces": {
+				Computed: true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"namespace": {
+						Type:        types.StringType,
+						Computed:    true,
+						Description: "Offloaded namespace.",
+					},
+					"pod_offloading_strategy": {
+						Type:        types.StringType,
+						Computed:    true,
+						Description: "Pod offloading strategy of the namespace.",
+					},
+					"namespace_mapping_strategy": {
+						Type:        types.StringType,
+						Computed:    true,
+						Description: "Naming strategy used to create the remote namespaces.",
+					},
+					"offloading_phase": {
+						Type:        types.StringType,
+						Computed:    true,
+						Description: "Overall offloading phase of the namespace, as reported by Liqo.",
+					},
+					"clusters": {
+						Computed: true,
+						Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+							"cluster_id": {
+								Type:        types.StringType,
+								Computed:    true,
+								Description: "ID of the remote cluster.",
+							},
+							"remote_namespace": {
+								Type:        types.StringType,
+								Computed:    true,
+								Description: "Name of the namespace in the remote cluster.",
+							},
+							"pods": {
+								Type:        types.Int64Type,
+								Computed:    true,
+								Description: "Number of pods of the namespace scheduled on the remote cluster.",
+							},
+							"cpu_requests": {
+								Type:        types.StringType,
+								Computed:    true,
+								Description: "CPU requested by the pods scheduled on the remote cluster.",
+							},
+							"memory_requests": {
+								Type:        types.StringType,
+								Computed:    true,
+								Description: "Memory requested by the pods scheduled on the remote cluster.",
+							},
+						}),
+						Description: "Remote clusters the namespace is offloaded to.",
+					},
+				}),
+				Description: "Offloaded namespaces, sorted by name.",
+			},
+		},
+	}, nil
+}
+
+// Read builds the report joining the NamespaceOffloadings, the NamespaceMaps and the pods running on virtual nodes.
+//
+//nolint:gocritic // Terraform Framework template code
+func (d *offloadingReportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data offloadingReportDataSourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := d.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := d.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	CRClient, _, err := d.data.clients(data.Cluster)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+
+	data.Namespaces, err = offloadingReport(ctx, CRClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Configure method to obtain kubernetes Clients provided by provider.
+func (d *offloadingReportDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.data = req.ProviderData.(*liqoProviderData)
+}
+
+// offloadingReport retrieves the offloaded namespaces, and aggregates the pods of each of them by remote cluster.
+func offloadingReport(ctx context.Context, cl client.Client) ([]offloadedNamespace, error) {
+	var nsoffs offloadingv1alpha1.NamespaceOffloadingList
+	if err := cl.List(ctx, &nsoffs); err != nil {
+		return nil, err
+	}
+
+	// The NamespaceMaps, one per remote cluster, track the name of the remote namespaces.
+	var nsmaps virtualkubeletv1alpha1.NamespaceMapList
+	if err := cl.List(ctx, &nsmaps); err != nil {
+		return nil, err
+	}
+	remoteNamespaces := map[string]map[string]virtualkubeletv1alpha1.RemoteNamespaceStatus{}
+	for i := range nsmaps.Items {
+		remoteNamespaces[nsmaps.Items[i].Labels[consts.RemoteClusterID]] = nsmaps.Items[i].Status.CurrentMapping
+	}
+
+	var nodes corev1.NodeList
+	if err := cl.List(ctx, &nodes, client.MatchingLabels{consts.TypeLabel: consts.TypeNode}); err != nil {
+		return nil, err
+	}
+	nodeClusters := map[string]string{}
+	for i := range nodes.Items {
+		nodeClusters[nodes.Items[i].Name] = nodes.Items[i].Labels[consts.RemoteClusterID]
+	}
+
+	report := []offloadedNamespace{}
+	for i := range nsoffs.Items {
+		nsoff := &nsoffs.Items[i]
+
+		var pods corev1.PodList
+		if err := cl.List(ctx, &pods, client.InNamespace(nsoff.Namespace)); err != nil {
+			return nil, err
+		}
+
+		type usage struct {
+			pods        int64
+			cpu, memory resource.Quantity
+		}
+		usages := map[string]*usage{}
+		for clusterID := range nsoff.Status.RemoteNamespacesConditions {
+			usages[clusterID] = &usage{}
+		}
+
+		for j := range pods.Items {
+			clusterID, found := nodeClusters[pods.Items[j].Spec.NodeName]
+			if !found {
+				continue
+			}
+			if usages[clusterID] == nil {
+				usages[clusterID] = &usage{}
+			}
+
+			u := usages[clusterID]
+			u.pods++
+			for k := range pods.Items[j].Spec.Containers {
+				u.cpu.Add(*pods.Items[j].Spec.Containers[k].Resources.Requests.Cpu())
+				u.memory.Add(*pods.Items[j].Spec.Containers[k].Resources.Requests.Memory())
+			}
+		}
+
+		entry := offloadedNamespace{
+			Namespace:                types.StringValue(nsoff.Namespace),
+			PodOffloadingStrategy:    types.StringValue(string(nsoff.Spec.PodOffloadingStrategy)),
+			NamespaceMappingStrategy: types.StringValue(string(nsoff.Spec.NamespaceMappingStrategy)),
+			OffloadingPhase:          types.StringValue(string(nsoff.Status.OffloadingPhase)),
+			Clusters:                 []offloadedNamespaceCluster{},
+		}
+		for clusterID, u := range usages {
+			entry.Clusters = append(entry.Clusters, offloadedNamespaceCluster{
+				ClusterID:       types.StringValue(clusterID),
+				RemoteNamespace: types.StringValue(remoteNamespaces[clusterID][nsoff.Namespace].RemoteNamespace),
+				Pods:            types.Int64Value(u.pods),
+				CPURequests:     types.StringValue(u.cpu.String()),
+				MemoryRequests:  types.StringValue(u.memory.String()),
+			})
+		}
+		sort.Slice(entry.Clusters, func(a, b int) bool {
+			return entry.Clusters[a].ClusterID.ValueString() < entry.Clusters[b].ClusterID.ValueString()
+		})
+
+		report = append(report, entry)
+	}
+
+	sort.Slice(report, func(a, b int) bool {
+		return report[a].Namespace.ValueString() < report[b].Namespace.ValueString()
+	})
+	return report, nil
+}
+
+type offloadedNamespaceCluster struct {
+	ClusterID       types.String `tfsdk:"cluster_id"`
+	RemoteNamespace types.String `tfsdk:"remote_namespace"`
+	Pods            types.Int64  `tfsdk:"pods"`
+	CPURequests     types.String `tfsdk:"cpu_requests"`
+	MemoryRequests  types.String `tfsdk:"memory_requests"`
+}
+
+type offloadedNamespace struct {
+	Namespace                types.String                `tfsdk:"namespace"`
+	PodOffloadingStrategy    types.String                `tfsdk:"pod_offloading_strategy"`
+	NamespaceMappingStrategy types.String                `tfsdk:"namespace_mapping_strategy"`
+	OffloadingPhase          types.String                `tfsdk:"offloading_phase"`
+	Clusters                 []offloadedNamespaceCluster `tfsdk:"clusters"`
+}
+
+type offloadingReportDataSourceModel struct {
+	Cluster    types.String         `tfsdk:"cluster"`
+	Namespaces []offloadedNamespace `tfsdk:"namespaces"`
+}
@@ -3,18 +3,26 @@ package liqo
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/discovery"
 	"github.com/liqotech/liqo/pkg/utils"
 	authenticationtokenutils "github.com/liqotech/liqo/pkg/utils/authenticationtoken"
@@ -23,17 +31,22 @@ import (
 )
 
 var (
-	_ resource.Resource              = &peerResource{}
-	_ resource.ResourceWithConfigure = &peerResource{}
+	_ resource.Resource                   = &peerResource{}
+	_ resource.ResourceWithConfigure      = &peerResource{}
+	_ resource.ResourceWithValidateConfig = &peerResource{}
+	_ resource.ResourceWithImportState    = &peerResource{}
 )
 
+// peerAuthURLRename tracks the renaming of cluster_authurl, kept as a deprecated alias of cluster_auth_url.
+var peerAuthURLRename = renamedAttribute{OldName: "cluster_authurl", NewName: "cluster_auth_url"}
+
 // NewPeerResource provides the initialization of Peer Resource.
 func NewPeerResource() resource.Resource {
 	return &peerResource{}
 }
 
 type peerResource struct {
-	config liqoProviderModel
+	data *liqoProviderData
 }
 
 func (p *peerResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,39 +57,190 @@ func (p *peerResource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnost
 	return tfsdk.Schema{
 		Description: "Execute peering.",
 		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+				},
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
 			"cluster_id": {
-				Type:        types.StringType,
-				Required:    true,
-				Description: "Provider cluster ID used for peering.",
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+					resource.RequiresReplace(),
+				},
+				Computed:    true,
+				Description: "Provider cluster ID used for peering. Required unless remote_kubernetes is set.",
 			},
 			"cluster_name": {
-				Type:        types.StringType,
-				Required:    true,
-				Description: "Provider cluster name used for peering.",
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+					resource.RequiresReplace(),
+				},
+				Computed:    true,
+				Description: "Provider cluster name used for peering. Required unless remote_kubernetes is set.",
 			},
-			"cluster_authurl": {
+			"cluster_auth_url": {
 				Type:        types.StringType,
-				Required:    true,
+				Optional:    true,
 				Description: "Provider authentication url used for peering.",
 			},
+			"cluster_authurl": peerAuthURLRename.deprecate(tfsdk.Attribute{
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Provider authentication url used for peering.",
+			}),
 			"cluster_token": {
 				Type:        types.StringType,
-				Required:    true,
-				Description: "Provider authentication token used for peering.",
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Provider authentication token used for peering. Required unless remote_kubernetes is set.",
+			},
+			"remote_kubernetes": {
+				Optional:   true,
+				Sensitive:  true,
+				Attributes: tfsdk.SingleNestedAttributes(kubernetesAttributes(false, false)),
+				Description: "Connection to the provider cluster, with the same settings of the provider kubernetes block, " +
+					"except that the KUBE_* environment variables are not read. " +
+					"If set, the cluster ID, name, authentication url and token not configured are retrieved from the provider cluster.",
 			},
 			"liqo_namespace": {
 				Type:     types.StringType,
 				Optional: true,
 				PlanModifiers: []tfsdk.AttributePlanModifier{
-					planmodifier.DefaultValue(types.StringValue("liqo")),
+					planmodifier.DefaultString(defaultLiqoNamespace()),
+					resource.RequiresReplace(),
 				},
 				Computed:    true,
-				Description: "Namespace where is Liqo installed in provider cluster.",
+				Description: "Namespace where Liqo is installed in provider cluster. Defaults to LIQO_NAMESPACE, or liqo.",
+			},
+			"verify_offloading": {
+				Type:     types.BoolType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					planmodifier.DefaultBool(false),
+				},
+				Computed: true,
+				Description: "Whether to verify, once the peering is established, that a test pod can be offloaded to the remote cluster. " +
+					"The creation fails if the peering is not usable within 5 minutes, or the operation timeout if shorter.",
+			},
+			"wait_timeouts": {
+				Optional: true,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"authentication":   waitTimeoutAttribute("Maximum duration of the authentication with the remote cluster (e.g., 2m)."),
+					"network":          waitTimeoutAttribute("Maximum duration of the network setup, including the gateway service provisioning (e.g., 10m)."),
+					"outgoing_peering": waitTimeoutAttribute("Maximum duration of the acceptance of the resource request by the remote cluster (e.g., 5m)."),
+				}),
+				Description: "If set, the creation waits for the peering to be established, bounding each phase by its own timeout. " +
+					"Phases without a timeout are only bounded by the operation timeout.",
+			},
+			"created_at": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Time the peering has been created by Terraform (RFC 3339).",
+			},
+			"ready_at": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Time the outgoing peering has been established (RFC 3339). Empty until then.",
+			},
+			"last_status_check": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Time the status of the peering has been last verified by Terraform (RFC 3339).",
+			},
+			"status": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Status of the outgoing peering (Establishing, Established or Error).",
+			},
+			"remote_pod_cidr": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Pod CIDR of the remote cluster as seen by the local cluster (remapped on conflicts), empty until the network is established.",
+			},
+			"remote_external_cidr": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "External CIDR of the remote cluster as seen by the local cluster (remapped on conflicts), empty until the network is established.",
+			},
+			"local_pod_cidr": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "Pod CIDR of the local cluster as seen by the remote cluster (remapped on conflicts), empty until the network is established.",
+			},
+			"local_external_cidr": {
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.UseStateForUnknown(),
+				},
+				Description: "External CIDR of the local cluster as seen by the remote cluster (remapped on conflicts), empty until the network is established.",
 			},
 		},
 	}, nil
 }
 
+func (p *peerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var remote types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("remote_kubernetes"), &remote)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The peering parameters are required only if they cannot be retrieved from the provider cluster.
+	resp.Diagnostics.Append(peerAuthURLRename.validate(ctx, req.Config, remote.IsNull())...)
+	resp.Diagnostics.Append(validateWaitTimeouts(ctx, req.Config)...)
+
+	if remote.IsNull() {
+		for _, name := range []string{"cluster_id", "cluster_name", "cluster_token"} {
+			var value types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+			if value.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name),
+					"Missing Required Attribute",
+					fmt.Sprintf("The argument %s is required, unless remote_kubernetes is set.", name),
+				)
+			}
+		}
+		return
+	}
+
+	// Unknown settings are converted to empty values, as they are validated once known.
+	kube, diags := kubeConfFromObject(ctx, remote)
+	resp.Diagnostics.Append(diags...)
+	if kube != nil {
+		validateKubeConf(kube, path.Root("remote_kubernetes"), &resp.Diagnostics)
+	}
+}
+
 // Creation of Peer Resource to execute peering between two clusters using auth parameters provided by Generate Resource
 // This resource will reproduce the same effect and outputs of "liqoctl peer out-of-band" command.
 //
@@ -89,103 +253,100 @@ func (p *peerResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	overrides, loader, err := CheckParameters(&p.config)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
-		return
-	}
+	ctx, cancel := p.data.withTimeout(ctx)
+	defer cancel()
 
-	CRClient, KubeClient, err := NewClients(overrides, loader)
+	release, err := p.data.acquire(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
+	defer release()
 
-	clusterIdentity, err := utils.GetClusterIdentityWithControllerClient(ctx, CRClient, plan.LiqoNamespace.ValueString())
+	CRClient, KubeClient, err := p.data.clients(plan.Cluster)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
 
-	if clusterIdentity.ClusterID == plan.ClusterID.ValueString() {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			"The Cluster ID of the remote cluster is the same of that of the local cluster",
-		)
+	start := time.Now()
+	fc, stored, diags := p.apply(ctx, CRClient, KubeClient, &plan, "create")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		if stored {
+			// The authentication token has already been stored: the peering is saved in the state, which marks it as tainted,
+			// so that the next apply can either retry or destroy it, rather than leaving untracked changes in the cluster.
+			plan.CreatedAt = types.StringValue(start.UTC().Format(time.RFC3339))
+			plan.observeStatus(nil)
+			plan.RemotePodCIDR, plan.RemoteExternalCIDR = types.StringValue(""), types.StringValue("")
+			plan.LocalPodCIDR, plan.LocalExternalCIDR = types.StringValue(""), types.StringValue("")
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		}
 		return
 	}
 
-	//nolint:lll // Long due to method invocation parameters.
-	err = authenticationtokenutils.StoreInSecret(ctx, KubeClient, plan.ClusterID.ValueString(), plan.ClusterToken.ValueString(), plan.LiqoNamespace.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
-		return
+	plan.CreatedAt = types.StringValue(start.UTC().Format(time.RFC3339))
+	plan.observeStatus(fc)
+
+	// The network is usually negotiated after the creation, hence the CIDRs are completed by the following refreshes.
+	if err := plan.setNetworkStatus(ctx, CRClient); err != nil {
+		resp.Diagnostics.AddWarning("Unable to Retrieve Peering Network", describeError(err))
 	}
 
-	fc, err := foreigncluster.GetForeignClusterByID(ctx, CRClient, plan.ClusterID.ValueString())
-	if kerrors.IsNotFound(err) {
-		fc = &discoveryv1alpha1.ForeignCluster{ObjectMeta: metav1.ObjectMeta{Name: plan.ClusterName.ValueString(),
-			Labels: map[string]string{discovery.ClusterIDLabel: plan.ClusterID.ValueString()}}}
-	} else if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, CRClient, fc, func() error {
-		if fc.Spec.PeeringType != discoveryv1alpha1.PeeringTypeUnknown && fc.Spec.PeeringType != discoveryv1alpha1.PeeringTypeOutOfBand {
-			return fmt.Errorf("a peering of type %s already exists towards remote cluster %q, cannot be changed to %s",
-				fc.Spec.PeeringType, plan.ClusterName.ValueString(), discoveryv1alpha1.PeeringTypeOutOfBand)
-		}
+	resp.Diagnostics.Append(p.data.recordEvent(ctx, plan.Cluster, fc, "TerraformCreate", "Outgoing peering enabled by Terraform")...)
+	resp.Diagnostics.Append(p.data.peeringHealth(ctx, plan.Cluster, CRClient)...)
 
-		fc.Spec.PeeringType = discoveryv1alpha1.PeeringTypeOutOfBand
-		fc.Spec.ClusterIdentity.ClusterID = plan.ClusterID.ValueString()
-		if fc.Spec.ClusterIdentity.ClusterName == "" {
-			fc.Spec.ClusterIdentity.ClusterName = plan.ClusterName.ValueString()
+	if plan.WaitTimeouts != nil || plan.VerifyOffloading.ValueBool() {
+		timeouts, err := plan.WaitTimeouts.durations()
+		if err == nil {
+			err = waitForPeeringPhases(ctx, CRClient, plan.ClusterID.ValueString(), timeouts)
+		}
+		if err != nil {
+			bundle := p.data.supportBundle(ctx, plan.Cluster, plan.RemoteKubernetes, plan.LiqoNamespace.ValueString(), plan.ClusterID.ValueString())
+			resp.Diagnostics.AddError(
+				"Unable to Establish Peering",
+				describeError(err)+bundle,
+			)
+			return
 		}
 
-		fc.Spec.ForeignAuthURL = plan.ClusterAuthURL.ValueString()
-		fc.Spec.ForeignProxyURL = ""
-		fc.Spec.OutgoingPeeringEnabled = discoveryv1alpha1.PeeringEnabledYes
-		if fc.Spec.IncomingPeeringEnabled == "" {
-			fc.Spec.IncomingPeeringEnabled = discoveryv1alpha1.PeeringEnabledAuto
+		if err := plan.setStatus(ctx, CRClient); err != nil {
+			resp.Diagnostics.AddWarning("Unable to Retrieve Peering Status", describeError(err))
 		}
-		if fc.Spec.InsecureSkipTLSVerify == nil {
-			fc.Spec.InsecureSkipTLSVerify = pointer.BoolPtr(true)
+		if err := plan.setNetworkStatus(ctx, CRClient); err != nil {
+			resp.Diagnostics.AddWarning("Unable to Retrieve Peering Network", describeError(err))
 		}
-		return nil
-	})
-
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Resource",
-			err.Error(),
-		)
-
-		return
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 	}
 
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	if plan.VerifyOffloading.ValueBool() {
+		if err := verifyOffloading(ctx, CRClient, plan.ClusterID.ValueString()); err != nil {
+			bundle := p.data.supportBundle(ctx, plan.Cluster, plan.RemoteKubernetes, plan.LiqoNamespace.ValueString(), plan.ClusterID.ValueString())
+			resp.Diagnostics.AddError(
+				"Unable to Verify Offloading",
+				describeError(err)+bundle,
+			)
+			return
+		}
 	}
 }
 
+// Read completes the state of imported peerings from the cluster, refreshes the status and the CIDRs negotiated
+// for the peering, and reports the peerings which are degraded. Peerings removed or disabled out-of-band are
+// dropped from the state, deleting the token secret left behind if the ForeignCluster no longer exists.
+//
 //nolint:gocritic // Terraform Framework template code
 func (p *peerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state peerResourceModel
@@ -195,19 +356,128 @@ func (p *peerResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	if !p.data.deferred {
+		ctx, cancel := p.data.withTimeout(ctx)
+		defer cancel()
+
+		release, err := p.data.acquire(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Resource",
+				describeError(err),
+			)
+			return
+		}
+		defer release()
+
+		CRClient, _, err := p.data.clients(state.Cluster)
+		imported := state.ClusterName.IsNull()
+		if err == nil && imported {
+			err = state.importFromCluster(ctx, CRClient)
+		}
+		var fc *discoveryv1alpha1.ForeignCluster
+		if err == nil {
+			fc, err = foreigncluster.GetForeignClusterByID(ctx, CRClient, state.ClusterID.ValueString())
+		}
+
+		// The peering has been removed out-of-band: it is dropped from the state, so that the next apply creates it again.
+		if kerrors.IsNotFound(err) || (err == nil && fc.Spec.OutgoingPeeringEnabled == discoveryv1alpha1.PeeringEnabledNo) {
+			// A missing ForeignCluster may also never have been created, if its configuration failed after storing the token:
+			// the token secret is deleted as well, as it would otherwise be left behind once the peering is dropped.
+			if kerrors.IsNotFound(err) && !imported {
+				if err := deleteAuthToken(ctx, CRClient, state.LiqoNamespace.ValueString(), state.ClusterID.ValueString()); err != nil {
+					resp.Diagnostics.AddError(
+						"Unable to Read Resource",
+						describeError(err),
+					)
+					return
+				}
+			}
+
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		if err == nil {
+			state.observeStatus(fc)
+			state.refreshFromCluster(fc)
+			err = state.setNetworkStatus(ctx, CRClient)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Resource",
+				describeError(err),
+			)
+			return
+		}
+	}
+
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	if CRClient, _, err := p.data.clients(state.Cluster); err == nil {
+		resp.Diagnostics.Append(p.data.peeringHealth(ctx, state.Cluster, CRClient)...)
+	}
 }
 
+// Update rotates the authentication url and token of the provider cluster in place, without re-peering.
+// The other attributes either require the replacement of the peering, or only affect the creation.
+//
 //nolint:gocritic // Terraform Framework template code
 func (p *peerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError(
-		"Unable to Update Resource",
-		"Update is not supported/permitted yet.",
-	)
+	var plan, state peerResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var planRemote, stateRemote types.Object
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("remote_kubernetes"), &planRemote)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("remote_kubernetes"), &stateRemote)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Moving a value from a deprecated attribute to its replacement does not change the peering.
+	if plan.authURL() == state.authURL() && plan.ClusterToken.Equal(state.ClusterToken) && planRemote.Equal(stateRemote) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	ctx, cancel := p.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := p.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Resource",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	CRClient, KubeClient, err := p.data.clients(plan.Cluster)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Resource",
+			describeError(err),
+		)
+		return
+	}
+
+	fc, _, diags := p.apply(ctx, CRClient, KubeClient, &plan, "update")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(p.data.recordEvent(ctx, plan.Cluster, fc, "TerraformUpdate", "Peering credentials updated by Terraform")...)
 }
 
 //nolint:gocritic // Terraform Framework template code
@@ -216,20 +486,24 @@ func (p *peerResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
-	overrides, loader, err := CheckParameters(&p.config)
+	ctx, cancel := p.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := p.data.acquire(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Delete Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
+	defer release()
 
-	CRClient, _, err := NewClients(overrides, loader)
+	CRClient, _, err := p.data.clients(data.Cluster)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Delete Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
@@ -244,13 +518,137 @@ func (p *peerResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 
 	foreignCluster.Spec.OutgoingPeeringEnabled = discoveryv1alpha1.PeeringEnabledNo
-	if err := CRClient.Update(ctx, &foreignCluster); err != nil {
+	start := time.Now()
+	err = retryOnTransientError(ctx, func() error { return CRClient.Update(ctx, &foreignCluster) })
+	resp.Diagnostics.Append(p.data.audit(data.Cluster, "delete", &foreignCluster, start, err, newRedactor())...)
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Delete Resource",
-			err.Error(),
+			describeError(err),
 		)
 		return
 	}
+
+	resp.Diagnostics.Append(p.data.recordEvent(ctx, data.Cluster, &foreignCluster, "TerraformDelete", "Outgoing peering disabled by Terraform")...)
+}
+
+// apply stores the authentication token of the provider cluster, and enables the out-of-band outgoing peering
+// in the corresponding ForeignCluster. It is shared by Create and Update, which only differ in the following steps.
+// The returned flag reports whether the token has been stored, even if the configuration of the ForeignCluster failed.
+func (p *peerResource) apply(ctx context.Context, CRClient client.Client, KubeClient kubernetes.Interface,
+	plan *peerResourceModel, operation string) (fc *discoveryv1alpha1.ForeignCluster, stored bool, diags diag.Diagnostics) {
+	title := "Unable to " + strings.ToUpper(operation[:1]) + operation[1:] + " Resource"
+
+	authURL, token, err := p.peeringCredentials(ctx, plan)
+	redact := newRedactor(append(plan.RemoteKubernetes.secrets(), token, authURL)...)
+	if err != nil {
+		diags.AddError("Unable to Retrieve Peering Parameters", redact.Error(err))
+		return nil, false, diags
+	}
+
+	clusterIdentity, err := utils.GetClusterIdentityWithControllerClient(ctx, CRClient, plan.LiqoNamespace.ValueString())
+	if err != nil {
+		diags.AddError(title, redact.Error(err))
+		return nil, false, diags
+	}
+
+	if clusterIdentity.ClusterID == plan.ClusterID.ValueString() {
+		diags.AddError(title, "The Cluster ID of the remote cluster is the same of that of the local cluster")
+		return nil, false, diags
+	}
+
+	err = retryOnTransientError(ctx, func() error {
+		return authenticationtokenutils.StoreInSecret(ctx, KubeClient, plan.ClusterID.ValueString(), token, plan.LiqoNamespace.ValueString())
+	})
+	if err != nil {
+		bundle := p.data.supportBundle(ctx, plan.Cluster, plan.RemoteKubernetes, plan.LiqoNamespace.ValueString(), plan.ClusterID.ValueString())
+		diags.AddError(title, redact.Error(err)+bundle)
+		return nil, false, diags
+	}
+
+	fc, err = foreigncluster.GetForeignClusterByID(ctx, CRClient, plan.ClusterID.ValueString())
+	if kerrors.IsNotFound(err) {
+		fc = &discoveryv1alpha1.ForeignCluster{ObjectMeta: metav1.ObjectMeta{Name: plan.ClusterName.ValueString(),
+			Labels: map[string]string{discovery.ClusterIDLabel: plan.ClusterID.ValueString()}}}
+	} else if err != nil {
+		diags.AddError(title, redact.Error(err))
+		return nil, true, diags
+	}
+
+	start := time.Now()
+	err = retryOnTransientError(ctx, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, CRClient, fc, func() error {
+			if fc.Spec.PeeringType != discoveryv1alpha1.PeeringTypeUnknown && fc.Spec.PeeringType != discoveryv1alpha1.PeeringTypeOutOfBand {
+				return fmt.Errorf("a peering of type %s already exists towards remote cluster %q, cannot be changed to %s",
+					fc.Spec.PeeringType, plan.ClusterName.ValueString(), discoveryv1alpha1.PeeringTypeOutOfBand)
+			}
+
+			p.data.applyDefaultMetadata(fc)
+			fc.Spec.PeeringType = discoveryv1alpha1.PeeringTypeOutOfBand
+			fc.Spec.ClusterIdentity.ClusterID = plan.ClusterID.ValueString()
+			if fc.Spec.ClusterIdentity.ClusterName == "" {
+				fc.Spec.ClusterIdentity.ClusterName = plan.ClusterName.ValueString()
+			}
+
+			fc.Spec.ForeignAuthURL = authURL
+			fc.Spec.ForeignProxyURL = ""
+			fc.Spec.OutgoingPeeringEnabled = discoveryv1alpha1.PeeringEnabledYes
+			if fc.Spec.IncomingPeeringEnabled == "" {
+				fc.Spec.IncomingPeeringEnabled = discoveryv1alpha1.PeeringEnabledAuto
+			}
+			if fc.Spec.InsecureSkipTLSVerify == nil {
+				fc.Spec.InsecureSkipTLSVerify = pointer.BoolPtr(true)
+			}
+			return nil
+		})
+		return err
+	})
+	diags.Append(p.data.audit(plan.Cluster, operation, fc, start, err, redact)...)
+	if err != nil {
+		bundle := p.data.supportBundle(ctx, plan.Cluster, plan.RemoteKubernetes, plan.LiqoNamespace.ValueString(), plan.ClusterID.ValueString())
+		diags.AddError(title, redact.Error(err)+bundle)
+		return nil, true, diags
+	}
+
+	return fc, true, diags
+}
+
+// peeringCredentials returns the authentication url and token of the provider cluster. If remote_kubernetes is set,
+// those not configured, as well as the cluster ID and name, are retrieved from the provider cluster.
+func (p *peerResource) peeringCredentials(ctx context.Context, plan *peerResourceModel) (authURL, token string, err error) {
+	authURL, token = plan.authURL(), plan.ClusterToken.ValueString()
+	if plan.RemoteKubernetes == nil {
+		return authURL, token, nil
+	}
+
+	clients, err := p.data.newClusterClients(plan.RemoteKubernetes, false)
+	if err != nil {
+		return authURL, token, err
+	}
+
+	params, err := getPeeringParameters(ctx, clients.CRClient, plan.LiqoNamespace.ValueString())
+	if err != nil {
+		return authURL, token, err
+	}
+
+	if !isSet(plan.ClusterID) {
+		plan.ClusterID = types.StringValue(params.ClusterID)
+	}
+	if !isSet(plan.ClusterName) {
+		plan.ClusterName = types.StringValue(params.ClusterName)
+	}
+	if authURL == "" {
+		authURL = params.AuthEP
+	}
+	if token == "" {
+		token = params.Token
+	}
+	return authURL, token, nil
+}
+
+// ImportState imports an existing peering, given the ID of the remote cluster.
+func (p *peerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importState(ctx, "cluster_id", req, resp)
 }
 
 // Configure method to obtain kubernetes Clients provided by provider.
@@ -259,13 +657,163 @@ func (p *peerResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	p.config = req.ProviderData.(liqoProviderModel)
+	p.data = req.ProviderData.(*liqoProviderData)
 }
 
 type peerResourceModel struct {
-	ClusterID      types.String `tfsdk:"cluster_id"`
-	ClusterName    types.String `tfsdk:"cluster_name"`
-	ClusterAuthURL types.String `tfsdk:"cluster_authurl"`
-	ClusterToken   types.String `tfsdk:"cluster_token"`
-	LiqoNamespace  types.String `tfsdk:"liqo_namespace"`
+	Cluster                  types.String      `tfsdk:"cluster"`
+	ClusterID                types.String      `tfsdk:"cluster_id"`
+	ClusterName              types.String      `tfsdk:"cluster_name"`
+	ClusterAuthURL           types.String      `tfsdk:"cluster_auth_url"`
+	ClusterAuthURLDeprecated types.String      `tfsdk:"cluster_authurl"`
+	ClusterToken             types.String      `tfsdk:"cluster_token"`
+	LiqoNamespace            types.String      `tfsdk:"liqo_namespace"`
+	RemoteKubernetes         *kubeConf         `tfsdk:"remote_kubernetes"`
+	VerifyOffloading         types.Bool        `tfsdk:"verify_offloading"`
+	WaitTimeouts             *peerWaitTimeouts `tfsdk:"wait_timeouts"`
+	CreatedAt                types.String      `tfsdk:"created_at"`
+	ReadyAt                  types.String      `tfsdk:"ready_at"`
+	LastStatusCheck          types.String      `tfsdk:"last_status_check"`
+	Status                   types.String      `tfsdk:"status"`
+	RemotePodCIDR            types.String      `tfsdk:"remote_pod_cidr"`
+	RemoteExternalCIDR       types.String      `tfsdk:"remote_external_cidr"`
+	LocalPodCIDR             types.String      `tfsdk:"local_pod_cidr"`
+	LocalExternalCIDR        types.String      `tfsdk:"local_external_cidr"`
+}
+
+// authURL returns the authentication url, whichever attribute it has been configured with.
+func (m *peerResourceModel) authURL() string {
+	return peerAuthURLRename.resolve(m.ClusterAuthURLDeprecated, m.ClusterAuthURL).ValueString()
+}
+
+// importFromCluster fills the attributes not set in the state, as after an import, from the ForeignCluster and the token Secret.
+func (m *peerResourceModel) importFromCluster(ctx context.Context, cl client.Client) error {
+	if m.LiqoNamespace.IsNull() {
+		m.LiqoNamespace = types.StringValue(defaultLiqoNamespace())
+	}
+
+	fc, err := foreigncluster.GetForeignClusterByID(ctx, cl, m.ClusterID.ValueString())
+	if err != nil {
+		return err
+	}
+
+	m.ClusterName = types.StringValue(fc.Name)
+	if m.ClusterAuthURL.IsNull() && m.ClusterAuthURLDeprecated.IsNull() {
+		m.ClusterAuthURL = types.StringValue(fc.Spec.ForeignAuthURL)
+	}
+
+	if m.ClusterToken.IsNull() {
+		token, err := authenticationtokenutils.GetAuthToken(ctx, m.ClusterID.ValueString(), cl)
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			return fmt.Errorf("no authentication token found for remote cluster %q", m.ClusterID.ValueString())
+		}
+		m.ClusterToken = types.StringValue(token)
+	}
+
+	return nil
+}
+
+// refreshFromCluster updates the attributes which may have been changed out-of-band in the ForeignCluster.
+// The authentication url is refreshed only if configured, as it is otherwise retrieved through remote_kubernetes.
+func (m *peerResourceModel) refreshFromCluster(fc *discoveryv1alpha1.ForeignCluster) {
+	m.ClusterName = types.StringValue(fc.Name)
+
+	switch {
+	case !m.ClusterAuthURL.IsNull():
+		m.ClusterAuthURL = types.StringValue(fc.Spec.ForeignAuthURL)
+	case !m.ClusterAuthURLDeprecated.IsNull():
+		m.ClusterAuthURLDeprecated = types.StringValue(fc.Spec.ForeignAuthURL)
+	}
+}
+
+// setStatus sets the status of the outgoing peering, which is in error if the ForeignCluster no longer exists.
+func (m *peerResourceModel) setStatus(ctx context.Context, cl client.Client) error {
+	fc, err := foreigncluster.GetForeignClusterByID(ctx, cl, m.ClusterID.ValueString())
+	if kerrors.IsNotFound(err) {
+		m.observeStatus(nil)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	m.observeStatus(fc)
+	return nil
+}
+
+// observeStatus sets the status of the outgoing peering towards the given ForeignCluster, nil if missing,
+// recording the time of the check, and the time the peering has been established the first time.
+func (m *peerResourceModel) observeStatus(fc *discoveryv1alpha1.ForeignCluster) {
+	now := time.Now().UTC()
+	m.LastStatusCheck = types.StringValue(now.Format(time.RFC3339))
+	if m.ReadyAt.IsUnknown() {
+		m.ReadyAt = types.StringNull()
+	}
+
+	if fc == nil {
+		m.Status = types.StringValue(peeringStatusError)
+		return
+	}
+
+	// Peerings created before the timestamps were tracked, or imported, fall back to the creation of the ForeignCluster.
+	if m.CreatedAt.IsNull() || m.CreatedAt.IsUnknown() {
+		m.CreatedAt = types.StringValue(fc.CreationTimestamp.UTC().Format(time.RFC3339))
+	}
+
+	m.Status = types.StringValue(peeringStatus(fc))
+	if m.Status.ValueString() == peeringStatusEstablished && m.ReadyAt.IsNull() {
+		readyAt := now
+		for i := range fc.Status.PeeringConditions {
+			condition := &fc.Status.PeeringConditions[i]
+			if condition.Type == discoveryv1alpha1.OutgoingPeeringCondition && !condition.LastTransitionTime.IsZero() {
+				readyAt = condition.LastTransitionTime.UTC()
+			}
+		}
+		m.ReadyAt = types.StringValue(readyAt.Format(time.RFC3339))
+	}
+}
+
+// setNetworkStatus sets the CIDRs negotiated for the peering, as tracked by the TunnelEndpoint towards the remote cluster.
+// They are left empty if the network has not been established yet.
+func (m *peerResourceModel) setNetworkStatus(ctx context.Context, cl client.Client) error {
+	m.RemotePodCIDR, m.RemoteExternalCIDR = types.StringValue(""), types.StringValue("")
+	m.LocalPodCIDR, m.LocalExternalCIDR = types.StringValue(""), types.StringValue("")
+
+	var teps netv1alpha1.TunnelEndpointList
+	if err := cl.List(ctx, &teps); err != nil {
+		return err
+	}
+
+	for i := range teps.Items {
+		spec := &teps.Items[i].Spec
+		if spec.ClusterIdentity.ClusterID != m.ClusterID.ValueString() {
+			continue
+		}
+
+		m.RemotePodCIDR = types.StringValue(remappedCIDR(spec.RemotePodCIDR, spec.RemoteNATPodCIDR))
+		m.RemoteExternalCIDR = types.StringValue(remappedCIDR(spec.RemoteExternalCIDR, spec.RemoteNATExternalCIDR))
+		m.LocalPodCIDR = types.StringValue(remappedCIDR(spec.LocalPodCIDR, spec.LocalNATPodCIDR))
+		m.LocalExternalCIDR = types.StringValue(remappedCIDR(spec.LocalExternalCIDR, spec.LocalNATExternalCIDR))
+		break
+	}
+
+	return nil
+}
+
+// deleteAuthToken deletes the secret storing the authentication token of the given remote cluster, if any.
+func deleteAuthToken(ctx context.Context, cl client.Client, namespace, clusterID string) error {
+	return retryOnTransientError(ctx, func() error {
+		return cl.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace(namespace),
+			client.MatchingLabels{discovery.ClusterIDLabel: clusterID}, client.HasLabels{discovery.AuthTokenLabel})
+	})
+}
+
+// remappedCIDR returns the CIDR effectively used for the peering, that is the remapped one if a remapping took place.
+func remappedCIDR(original, remapped string) string {
+	if remapped == "" || remapped == consts.DefaultCIDRValue {
+		return original
+	}
+	return remapped
 }
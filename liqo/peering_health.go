@@ -0,0 +1,101 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+// unhealthyPeeringStatuses are the statuses of the peering conditions denoting a degraded peering.
+// Pending conditions are not reported, as they are expected while the peering is being established.
+var unhealthyPeeringStatuses = map[discoveryv1alpha1.PeeringConditionStatusType]bool{
+	discoveryv1alpha1.PeeringConditionStatusError:       true,
+	discoveryv1alpha1.PeeringConditionStatusDenied:      true,
+	discoveryv1alpha1.PeeringConditionStatusEmptyDenied: true,
+}
+
+// Statuses of an outgoing peering, as reported by the status attribute of the peer resource.
+const (
+	peeringStatusEstablishing = "Establishing"
+	peeringStatusEstablished  = "Established"
+	peeringStatusError        = "Error"
+)
+
+// peeringStatus summarizes the status of the outgoing peering towards the given ForeignCluster.
+func peeringStatus(fc *discoveryv1alpha1.ForeignCluster) string {
+	if foreigncluster.IsOutgoingJoined(fc) {
+		return peeringStatusEstablished
+	}
+
+	for i := range fc.Status.PeeringConditions {
+		if unhealthyPeeringStatuses[fc.Status.PeeringConditions[i].Status] {
+			return peeringStatusError
+		}
+	}
+	return peeringStatusEstablishing
+}
+
+// peeringHealthSummary tracks the degraded peerings already reported, so that each one is listed in a single warning.
+type peeringHealthSummary struct {
+	mutex    sync.Mutex
+	reported map[string]bool
+}
+
+// peeringHealth returns a single warning listing all the degraded peerings of the given cluster connection.
+// Each peering is reported once per provider run: the first peer resource created or refreshed gets the whole
+// summary, while the following ones only report the peerings degraded in the meantime.
+// Failures listing the ForeignClusters are ignored, as the health check is best effort.
+func (d *liqoProviderData) peeringHealth(ctx context.Context, cluster types.String, cl client.Client) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var foreignClusters discoveryv1alpha1.ForeignClusterList
+	if err := cl.List(ctx, &foreignClusters); err != nil {
+		return diags
+	}
+
+	d.health.mutex.Lock()
+	defer d.health.mutex.Unlock()
+	if d.health.reported == nil {
+		d.health.reported = map[string]bool{}
+	}
+
+	var degraded []string
+	for i := range foreignClusters.Items {
+		fc := &foreignClusters.Items[i]
+		key := cluster.ValueString() + "/" + fc.Spec.ClusterIdentity.ClusterID
+
+		var conditions []string
+		for j := range fc.Status.PeeringConditions {
+			condition := &fc.Status.PeeringConditions[j]
+			if unhealthyPeeringStatuses[condition.Status] {
+				conditions = append(conditions, fmt.Sprintf("  - %s: %s (%s)", condition.Type, condition.Status, condition.Message))
+			}
+		}
+		if len(conditions) == 0 || d.health.reported[key] {
+			continue
+		}
+
+		d.health.reported[key] = true
+		degraded = append(degraded, fmt.Sprintf("- %s:\n%s", fc.Spec.ClusterIdentity.ClusterName, strings.Join(conditions, "\n")))
+	}
+
+	if len(degraded) > 0 {
+		connection := "the provider cluster"
+		if isSet(cluster) {
+			connection = fmt.Sprintf("cluster %q", cluster.ValueString())
+		}
+		diags.AddWarning(
+			"Peerings Not Healthy",
+			fmt.Sprintf("The following peerings of %s are degraded:\n%s", connection, strings.Join(degraded, "\n")),
+		)
+	}
+	return diags
+}
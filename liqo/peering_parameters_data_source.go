@@ -0,0 +1,187 @@
+package liqo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/auth"
+	"github.com/liqotech/liqo/pkg/utils"
+	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+var (
+	_ datasource.DataSource              = &peeringParametersDataSource{}
+	_ datasource.DataSourceWithConfigure = &peeringParametersDataSource{}
+)
+
+// NewPeeringParametersDataSource provides the initialization of Peering Parameters Data Source.
+func NewPeeringParametersDataSource() datasource.DataSource {
+	return &peeringParametersDataSource{}
+}
+
+type peeringParametersDataSource struct {
+	data *liqoProviderData
+}
+
+func (d *peeringParametersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_peering_parameters"
+}
+
+func (d *peeringParametersDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		Description: "Retrieve the parameters required by remote clusters to peer with the local one.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
+			"cluster_id": {
+				Type:        types.StringType,
+				Computed:    true,
+				Description: "Provider cluster ID.",
+			},
+			"cluster_name": {
+				Type:        types.StringType,
+				Computed:    true,
+				Description: "Provider cluster name.",
+			},
+			"auth_ep": {
+				Type:        types.StringType,
+				Computed:    true,
+				Description: "Provider authentication endpoint.",
+			},
+			"local_token": {
+				Type:        types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Provider authentication token.",
+			},
+			"liqo_namespace": {
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "Namespace where Liqo is installed in provider cluster. Defaults to LIQO_NAMESPACE, or liqo.",
+			},
+		},
+	}, nil
+}
+
+// Read retrieves the pairing parameters used by Peer Resources.
+// This data source will reproduce the same effect and outputs of "liqoctl generate peer-command" command.
+//
+//nolint:gocritic // Terraform Framework template code
+func (d *peeringParametersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data peeringParametersDataSourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.LiqoNamespace.IsNull() {
+		data.LiqoNamespace = types.StringValue(defaultLiqoNamespace())
+	}
+
+	ctx, cancel := d.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := d.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	CRClient, _, err := d.data.clients(data.Cluster)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+
+	params, err := getPeeringParameters(ctx, CRClient, data.LiqoNamespace.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+
+	data.ClusterID = types.StringValue(params.ClusterID)
+	data.ClusterName = types.StringValue(params.ClusterName)
+	data.AuthEP = types.StringValue(params.AuthEP)
+	data.LocalToken = types.StringValue(params.Token)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Configure method to obtain kubernetes Clients provided by provider.
+func (d *peeringParametersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.data = req.ProviderData.(*liqoProviderData)
+}
+
+// peeringParameters contains the information a remote cluster needs to establish an out-of-band peering.
+type peeringParameters struct {
+	ClusterID   string
+	ClusterName string
+	AuthEP      string
+	Token       string
+}
+
+// getPeeringParameters retrieves the identity, authentication endpoint and token of the local cluster.
+func getPeeringParameters(ctx context.Context, cl client.Client, liqoNamespace string) (*peeringParameters, error) {
+	clusterIdentity, err := utils.GetClusterIdentityWithControllerClient(ctx, cl, liqoNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	localToken, err := auth.GetToken(ctx, cl, liqoNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	authEP, err := foreigncluster.GetHomeAuthURL(ctx, cl, liqoNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if clusterIdentity.ClusterName == "" {
+		clusterIdentity.ClusterName = clusterIdentity.ClusterID
+	}
+
+	return &peeringParameters{
+		ClusterID:   clusterIdentity.ClusterID,
+		ClusterName: clusterIdentity.ClusterName,
+		AuthEP:      authEP,
+		Token:       localToken,
+	}, nil
+}
+
+type peeringParametersDataSourceModel struct {
+	Cluster       types.String `tfsdk:"cluster"`
+	ClusterID     types.String `tfsdk:"cluster_id"`
+	ClusterName   types.String `tfsdk:"cluster_name"`
+	AuthEP        types.String `tfsdk:"auth_ep"`
+	LocalToken    types.String `tfsdk:"local_token"`
+	LiqoNamespace types.String `tfsdk:"liqo_namespace"`
+}
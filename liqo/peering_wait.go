@@ -0,0 +1,145 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	planmodifier "github.com/liqotech/terraform-provider-liqo/liqo/attribute_plan_modifier"
+)
+
+// peeringPhase is a phase of the establishment of an outgoing peering, tracked by the conditions of the ForeignCluster.
+type peeringPhase struct {
+	name      string
+	completed func(fc *discoveryv1alpha1.ForeignCluster) bool
+}
+
+// peeringPhases are the phases of an outgoing peering, in the order they complete.
+var peeringPhases = []peeringPhase{
+	{name: "authentication", completed: foreigncluster.IsAuthenticated},
+	{name: "network", completed: foreigncluster.IsNetworkingEstablishedOrExternal},
+	{name: "outgoing_peering", completed: foreigncluster.IsOutgoingJoined},
+}
+
+// waitForPeeringPhases waits for the phases of the outgoing peering towards the given cluster to complete in turn.
+// Each phase is bounded by its own timeout, if any, so that the error points out the phase which is stuck.
+func waitForPeeringPhases(ctx context.Context, cl client.Client, clusterID string, timeouts map[string]time.Duration) error {
+	for _, phase := range peeringPhases {
+		phaseCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout, found := timeouts[phase.name]; found {
+			phaseCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		err := wait.PollUntilContextCancel(phaseCtx, offloadingCheckInterval, true, func(ctx context.Context) (bool, error) {
+			fc, err := foreigncluster.GetForeignClusterByID(ctx, cl, clusterID)
+			if err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			return phase.completed(fc), nil
+		})
+		cancel()
+		if err != nil {
+			return &peeringPhaseError{phase: phase.name, clusterID: clusterID, err: err}
+		}
+	}
+	return nil
+}
+
+// peeringPhaseError reports a phase of the peering which did not complete, so that the hint can point out its usual causes.
+type peeringPhaseError struct {
+	phase     string
+	clusterID string
+	err       error
+}
+
+func (e *peeringPhaseError) Error() string {
+	return fmt.Sprintf("waiting for the %s phase of the peering with cluster %q: %s",
+		strings.ReplaceAll(e.phase, "_", " "), e.clusterID, e.err)
+}
+
+func (e *peeringPhaseError) Unwrap() error {
+	return e.err
+}
+
+// peerWaitTimeouts are the timeouts of the phases of an outgoing peering.
+type peerWaitTimeouts struct {
+	Authentication  types.String `tfsdk:"authentication"`
+	Network         types.String `tfsdk:"network"`
+	OutgoingPeering types.String `tfsdk:"outgoing_peering"`
+}
+
+// waitTimeoutAttribute returns the schema of the timeout of a peering phase.
+// Equivalent durations (e.g., 5m and 300s) are normalized to the prior value, so that they do not cause diffs.
+func waitTimeoutAttribute(description string) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Type:     types.StringType,
+		Optional: true,
+		PlanModifiers: []tfsdk.AttributePlanModifier{
+			planmodifier.DefaultValue(types.StringNull()),
+			planmodifier.NormalizeDuration(),
+		},
+		Computed:    true,
+		Description: description,
+	}
+}
+
+// durations returns the configured timeouts, keyed by phase name.
+func (t *peerWaitTimeouts) durations() (map[string]time.Duration, error) {
+	durations := map[string]time.Duration{}
+	if t == nil {
+		return durations, nil
+	}
+
+	for name, value := range map[string]types.String{
+		"authentication":   t.Authentication,
+		"network":          t.Network,
+		"outgoing_peering": t.OutgoingPeering,
+	} {
+		if !isSet(value) {
+			continue
+		}
+
+		timeout, err := time.ParseDuration(value.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s timeout %q: %w", name, value.ValueString(), err)
+		}
+		durations[name] = timeout
+	}
+	return durations, nil
+}
+
+// validateWaitTimeouts checks that the timeouts of the phases of the peering, if known, are valid durations.
+func validateWaitTimeouts(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var object types.Object
+	diags.Append(config.GetAttribute(ctx, path.Root("wait_timeouts"), &object)...)
+	if diags.HasError() || object.IsNull() || object.IsUnknown() {
+		return diags
+	}
+
+	var timeouts peerWaitTimeouts
+	diags.Append(object.As(ctx, &timeouts, types.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if _, err := timeouts.durations(); err != nil {
+		diags.AddAttributeError(
+			path.Root("wait_timeouts"),
+			"Invalid Timeout",
+			err.Error(),
+		)
+	}
+	return diags
+}
@@ -5,66 +5,102 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/mitchellh/go-homedir"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
-	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	offloadingv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	virtualkubeletv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/utils"
 	planmodifier "github.com/liqotech/terraform-provider-liqo/liqo/attribute_plan_modifier"
 )
 
-func init() {
-	utilruntime.Must(discoveryv1alpha1.AddToScheme(scheme.Scheme))
-	utilruntime.Must(netv1alpha1.AddToScheme(scheme.Scheme))
-	utilruntime.Must(offloadingv1alpha1.AddToScheme(scheme.Scheme))
-	utilruntime.Must(sharingv1alpha1.AddToScheme(scheme.Scheme))
+// newScheme builds the scheme of the provider clients, registering both the Kubernetes and the Liqo types.
+// Each provider instance owns a dedicated scheme, so that the global one is never mutated.
+func newScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(discoveryv1alpha1.AddToScheme(s))
+	utilruntime.Must(netv1alpha1.AddToScheme(s))
+	utilruntime.Must(offloadingv1alpha1.AddToScheme(s))
+	utilruntime.Must(sharingv1alpha1.AddToScheme(s))
+	utilruntime.Must(virtualkubeletv1alpha1.AddToScheme(s))
+	return s
 }
 
 var (
-	_ provider.Provider = &liqoProvider{}
+	_ provider.Provider                   = &liqoProvider{}
+	_ provider.ProviderWithValidateConfig = &liqoProvider{}
 )
 
-// New provides the initialization of provider.
-func New() provider.Provider {
-	return &liqoProvider{}
+// New provides the initialization of provider, given its version.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &liqoProvider{version: version}
+	}
 }
 
 type liqoProvider struct {
+	version string
 }
 
 // CheckParameters method used to check if kubernetes parameters are null.
-func CheckParameters(config *liqoProviderModel) (*clientcmd.ConfigOverrides, *clientcmd.ClientConfigLoadingRules, error) {
+// If useEnv is set, unset parameters fall back to the corresponding KUBE_* environment variables.
+func CheckParameters(kube *kubeConf, useEnv bool) (*clientcmd.ConfigOverrides, *clientcmd.ClientConfigLoadingRules, error) {
 	overrides := &clientcmd.ConfigOverrides{}
 	loader := &clientcmd.ClientConfigLoadingRules{}
 
+	lookup := envString
+	if !useEnv {
+		lookup = func(attribute types.String, _ ...string) (string, bool) {
+			return envString(attribute)
+		}
+	}
+
+	if kube == nil {
+		// Without an explicit configuration, honor KUBECONFIG and ~/.kube/config, falling back to the in-cluster configuration.
+		kube = &kubeConf{}
+		loader = clientcmd.NewDefaultClientConfigLoadingRules()
+	}
+
 	configPaths := []string{}
 
-	if !config.Kubernetes.KubeConfigPath.IsNull() {
-		configPaths = []string{config.Kubernetes.KubeConfigPath.ValueString()}
-	} else if len(config.Kubernetes.KubeConfigPaths) > 0 {
-		for _, configPath := range config.Kubernetes.KubeConfigPaths {
+	if v, ok := lookup(kube.KubeConfigPath, "KUBE_CONFIG_PATH"); ok {
+		configPaths = []string{v}
+	} else if len(kube.KubeConfigPaths) > 0 {
+		for _, configPath := range kube.KubeConfigPaths {
 			configPaths = append(configPaths, configPath.ValueString())
 		}
-	} else if v := os.Getenv("KubeConfigPaths"); v != "" {
+	} else if v, ok := lookup(types.StringNull(), "KUBE_CONFIG_PATHS", "KUBECONFIG"); ok {
 		configPaths = filepath.SplitList(v)
 	}
 
@@ -83,74 +119,108 @@ func CheckParameters(config *liqoProviderModel) (*clientcmd.ConfigOverrides, *cl
 		} else {
 			loader.Precedence = expandedPaths
 		}
+	}
 
-		ctxNotOk := config.Kubernetes.KubeCtx.IsNull()
-		authInfoNotOk := config.Kubernetes.KubeCtxAuthInfo.IsNull()
-		clusterNotOk := config.Kubernetes.KubeCtxCluster.IsNull()
-
-		if ctxNotOk || authInfoNotOk || clusterNotOk {
-			if ctxNotOk {
-				overrides.CurrentContext = config.Kubernetes.KubeCtx.ValueString()
-			}
+	if v, ok := lookup(kube.KubeCtx, "KUBE_CTX"); ok {
+		overrides.CurrentContext = v
+	}
+	if v, ok := lookup(kube.KubeCtxAuthInfo, "KUBE_CTX_AUTH_INFO"); ok {
+		overrides.Context.AuthInfo = v
+	}
+	if v, ok := lookup(kube.KubeCtxCluster, "KUBE_CTX_CLUSTER"); ok {
+		overrides.Context.Cluster = v
+	}
 
-			overrides.Context = clientcmdapi.Context{}
-			if authInfoNotOk {
-				overrides.Context.AuthInfo = config.Kubernetes.KubeCtxAuthInfo.ValueString()
-			}
-			if clusterNotOk {
-				overrides.Context.Cluster = config.Kubernetes.KubeCtxCluster.ValueString()
-			}
+	if !kube.KubeInsecure.IsNull() {
+		overrides.ClusterInfo.InsecureSkipTLSVerify = kube.KubeInsecure.ValueBool()
+	} else if v, ok := lookup(types.StringNull(), "KUBE_INSECURE"); ok {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid KUBE_INSECURE value: %w", err)
 		}
+		overrides.ClusterInfo.InsecureSkipTLSVerify = insecure
 	}
-
-	if !config.Kubernetes.KubeInsecure.IsNull() {
-		overrides.ClusterInfo.InsecureSkipTLSVerify = !config.Kubernetes.KubeInsecure.ValueBool()
+	if v, ok := lookup(kube.KubeTLSServerName, "KUBE_TLS_SERVER_NAME"); ok {
+		overrides.ClusterInfo.TLSServerName = v
+	}
+	if v, ok := lookup(kube.KubeClusterCaCertData, "KUBE_CLUSTER_CA_CERT_DATA"); ok {
+		overrides.ClusterInfo.CertificateAuthorityData = bytes.NewBufferString(v).Bytes()
+	}
+	if v, ok := lookup(kube.KubeClusterCaCertFile, "KUBE_CLUSTER_CA_CERT_FILE"); ok {
+		path, err := homedir.Expand(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides.ClusterInfo.CertificateAuthority = path
 	}
-	if !config.Kubernetes.KubeClusterCaCertData.IsNull() {
-		overrides.ClusterInfo.CertificateAuthorityData = bytes.NewBufferString(config.Kubernetes.KubeClusterCaCertData.ValueString()).Bytes()
+	if v, ok := lookup(kube.KubeClientCertData, "KUBE_CLIENT_CERT_DATA"); ok {
+		overrides.AuthInfo.ClientCertificateData = bytes.NewBufferString(v).Bytes()
 	}
-	if !config.Kubernetes.KubeClientCertData.IsNull() {
-		overrides.AuthInfo.ClientCertificateData = bytes.NewBufferString(config.Kubernetes.KubeClientCertData.ValueString()).Bytes()
+	if v, ok := lookup(kube.KubeClientCertFile, "KUBE_CLIENT_CERT_FILE"); ok {
+		path, err := homedir.Expand(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides.AuthInfo.ClientCertificate = path
 	}
-	if !config.Kubernetes.KubeHost.IsNull() {
-		hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0
-		hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0
+	if v, ok := lookup(kube.KubeHost, "KUBE_HOST"); ok {
+		hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0 || overrides.ClusterInfo.CertificateAuthority != ""
+		hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0 || overrides.AuthInfo.ClientCertificate != ""
 		defaultTLS := hasCA || hasCert || overrides.ClusterInfo.InsecureSkipTLSVerify
-		host, _, err := rest.DefaultServerURL(config.Kubernetes.KubeHost.ValueString(), "", apimachineryschema.GroupVersion{}, defaultTLS)
+		host, _, err := rest.DefaultServerURL(v, "", apimachineryschema.GroupVersion{}, defaultTLS)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		overrides.ClusterInfo.Server = host.String()
 	}
-	if !config.Kubernetes.KubeUser.IsNull() {
-		overrides.AuthInfo.Username = config.Kubernetes.KubeUser.ValueString()
+	if v, ok := lookup(kube.KubeUser, "KUBE_USER"); ok {
+		overrides.AuthInfo.Username = v
 	}
-	if !config.Kubernetes.KubePassword.IsNull() {
-		overrides.AuthInfo.Password = config.Kubernetes.KubePassword.ValueString()
+	if v, ok := lookup(kube.KubePassword, "KUBE_PASSWORD"); ok {
+		overrides.AuthInfo.Password = v
 	}
-	if !config.Kubernetes.KubeClientKeyData.IsNull() {
-		overrides.AuthInfo.ClientKeyData = bytes.NewBufferString(config.Kubernetes.KubeClientKeyData.ValueString()).Bytes()
+	if v, ok := lookup(kube.KubeClientKeyData, "KUBE_CLIENT_KEY_DATA"); ok {
+		overrides.AuthInfo.ClientKeyData = bytes.NewBufferString(v).Bytes()
 	}
-	if !config.Kubernetes.KubeToken.IsNull() {
-		overrides.AuthInfo.Token = config.Kubernetes.KubeToken.ValueString()
+	if v, ok := lookup(kube.KubeClientKeyFile, "KUBE_CLIENT_KEY_FILE"); ok {
+		path, err := homedir.Expand(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides.AuthInfo.ClientKey = path
+	}
+	if v, ok := lookup(kube.KubeToken, "KUBE_TOKEN"); ok {
+		overrides.AuthInfo.Token = v
 	}
 
-	if !config.Kubernetes.KubeProxyURL.IsNull() {
-		overrides.ClusterDefaults.ProxyURL = config.Kubernetes.KubeProxyURL.ValueString()
+	if v, ok := lookup(kube.KubeProxyURL, "KUBE_PROXY_URL"); ok {
+		overrides.ClusterDefaults.ProxyURL = v
 	}
 
-	if len(config.Kubernetes.KubeExec) > 0 {
+	if v, ok := lookup(kube.KubeImpersonate, "KUBE_IMPERSONATE"); ok {
+		overrides.AuthInfo.Impersonate = v
+	}
+	if v, ok := lookup(kube.KubeImpersonateUID, "KUBE_IMPERSONATE_UID"); ok {
+		overrides.AuthInfo.ImpersonateUID = v
+	}
+	for _, group := range kube.KubeImpersonateGroups {
+		overrides.AuthInfo.ImpersonateGroups = append(overrides.AuthInfo.ImpersonateGroups, group.ValueString())
+	}
+
+	if kube.KubeExec != nil {
 		exec := &clientcmdapi.ExecConfig{}
 		exec.InteractiveMode = clientcmdapi.IfAvailableExecInteractiveMode
-		exec.APIVersion = config.Kubernetes.KubeExec[0].APIVersion.ValueString()
-		exec.Command = config.Kubernetes.KubeExec[0].Command.ValueString()
-		for _, arg := range config.Kubernetes.KubeExec[0].Args {
+		exec.APIVersion = kube.KubeExec.APIVersion.ValueString()
+		exec.Command = kube.KubeExec.Command.ValueString()
+		for _, arg := range kube.KubeExec.Args {
 			exec.Args = append(exec.Args, arg.ValueString())
 		}
 
-		for kk, vv := range config.Kubernetes.KubeExec[0].Env.Elements() {
-			exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: kk, Value: vv.String()})
+		for kk, vv := range kube.KubeExec.Env.Elements() {
+			if v, ok := vv.(types.String); ok {
+				exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: kk, Value: v.ValueString()})
+			}
 		}
 
 		overrides.AuthInfo.Exec = exec
@@ -159,23 +229,86 @@ func CheckParameters(config *liqoProviderModel) (*clientcmd.ConfigOverrides, *cl
 	return overrides, loader, nil
 }
 
-// NewClients method to create CRClient and KubeClient.
-func NewClients(overrides *clientcmd.ConfigOverrides, loader *clientcmd.ClientConfigLoadingRules) (client.Client, *kubernetes.Clientset, error) {
-	clientCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
-	if clientCfg == nil {
-		return nil, nil, errors.New("error while creating clientCfg")
+// envString returns the value of the given attribute if set, otherwise the value of the first non-empty environment variable.
+func envString(attribute types.String, envs ...string) (string, bool) {
+	if isSet(attribute) {
+		return attribute.ValueString(), true
 	}
 
-	var restCfg *rest.Config
+	for _, env := range envs {
+		if v := os.Getenv(env); v != "" {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// defaultLiqoNamespace returns the namespace where Liqo is assumed to be installed, which can be set with LIQO_NAMESPACE.
+func defaultLiqoNamespace() string {
+	if v := os.Getenv("LIQO_NAMESPACE"); v != "" {
+		return v
+	}
+
+	return "liqo"
+}
+
+// RESTConfig method to build the rest configuration used to create CRClient and KubeClient.
+// The KUBE_* environment variables are only honored if useEnv is set.
+func RESTConfig(kube *kubeConf, useEnv bool) (*rest.Config, error) {
+	if kube != nil && kube.InCluster.ValueBool() {
+		return rest.InClusterConfig()
+	}
+
+	overrides, loader, err := CheckParameters(kube, useEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	content := types.StringNull()
+	if kube != nil {
+		content = kube.KubeConfigContent
+	}
+
+	contentEnv := []string{}
+	if useEnv {
+		contentEnv = append(contentEnv, "KUBE_CONFIG_CONTENT")
+	}
+
+	var clientCfg clientcmd.ClientConfig
+	if v, ok := envString(content, contentEnv...); ok {
+		kubeConfig, err := clientcmd.Load([]byte(v))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the kube config content: %w", err)
+		}
+
+		clientCfg = clientcmd.NewNonInteractiveClientConfig(*kubeConfig, overrides.CurrentContext, overrides, nil)
+	} else {
+		clientCfg = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	}
+	if clientCfg == nil {
+		return nil, errors.New("error while creating clientCfg")
+	}
 
 	restCfg, err := clientCfg.ClientConfig()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var CRClient client.Client
+	if kube != nil && kube.KubeAWSEKS != nil {
+		generate, err := newEKSTokenGenerator(kube.KubeAWSEKS)
+		if err != nil {
+			return nil, err
+		}
+		restCfg.Wrap(eksTokenWrapper(generate))
+	}
 
-	CRClient, err = client.New(restCfg, client.Options{Scheme: scheme.Scheme})
+	return restCfg, nil
+}
+
+// NewClients method to create CRClient, using the given scheme, and KubeClient.
+func NewClients(restCfg *rest.Config, scheme *runtime.Scheme) (client.Client, *kubernetes.Clientset, error) {
+	CRClient, err := client.New(restCfg, client.Options{Scheme: scheme})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -196,162 +329,403 @@ func (p *liqoProvider) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnost
 	return tfsdk.Schema{
 		Description: "Interact with Liqo.",
 		Attributes: map[string]tfsdk.Attribute{
-			"kubernetes": {
+			"max_concurrent_operations": {
+				Type:     types.Int64Type,
 				Optional: true,
-				Computed: true,
-				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
-					"host": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "The hostname (in form of URI) of Kubernetes master.",
-					},
-					"username": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "The username to use for HTTP basic authentication when accessing the Kubernetes master endpoint.",
-					},
-					"password": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "The password to use for HTTP basic authentication when accessing the Kubernetes master endpoint.",
-					},
-					"insecure": {
-						Type:     types.BoolType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.BoolValue(false)),
-						},
-						Description: "Whether server should be accessed without verifying the TLS certificate.",
-					},
-					"client_certificate": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "PEM-encoded client certificate for TLS authentication.",
-					},
-					"client_key": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "PEM-encoded client certificate key for TLS authentication.",
-					},
-					"cluster_ca_certificate": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "PEM-encoded root certificates bundle for TLS authentication.",
-					},
-					"config_paths": {
-						Type:     types.ListType{ElemType: types.StringType},
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.ListNull(types.StringType)),
-						},
-					},
-					"config_path": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "Path to the kube config file. Can be set with KubeConfigPath.",
-					},
-					"config_context": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-					},
-					"config_context_auth_info": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "",
-					},
-					"config_context_cluster": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "",
-					},
-					"token": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "Token to authenticate an service account",
-					},
-					"proxy_url": {
-						Type:     types.StringType,
-						Optional: true,
-						PlanModifiers: []tfsdk.AttributePlanModifier{
-							planmodifier.DefaultValue(types.StringValue("")),
-						},
-						Description: "URL to the proxy to be used for all API requests",
-					},
-					"exec": {
-						Optional: true,
-						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
-							"api_version": {
-								Type:     types.StringType,
-								Required: true,
-								PlanModifiers: []tfsdk.AttributePlanModifier{
-									planmodifier.DefaultValue(types.StringValue("")),
-								},
-								Validators: []tfsdk.AttributeValidator{
-									stringvalidator.NoneOf("client.authentication.k8s.io/v1alpha1"),
-								},
-							},
-							"command": {
-								Type:     types.StringType,
-								Required: true,
-								PlanModifiers: []tfsdk.AttributePlanModifier{
-									planmodifier.DefaultValue(types.StringValue("")),
-								},
-							},
-							"env": {
-								Type:     types.MapType{ElemType: types.StringType},
-								Optional: true,
-								PlanModifiers: []tfsdk.AttributePlanModifier{
-									planmodifier.DefaultValue(types.MapNull(types.StringType)),
-								},
-							},
-							"args": {
-								Type:     types.ListType{ElemType: types.StringType},
-								Optional: true,
-								PlanModifiers: []tfsdk.AttributePlanModifier{
-									planmodifier.DefaultValue(types.ListNull(types.StringType)),
-								},
-							},
-						}),
-					},
-				}),
+				Validators: []tfsdk.AttributeValidator{
+					int64validator.AtLeast(1),
+				},
+				Description: "Maximum number of resource operations performed concurrently against the cluster. Unlimited if not set.",
+			},
+			"default_labels": {
+				Type:        types.MapType{ElemType: types.StringType},
+				Optional:    true,
+				Description: "Labels added to every object created by the provider.",
+			},
+			"default_annotations": {
+				Type:        types.MapType{ElemType: types.StringType},
+				Optional:    true,
+				Description: "Annotations added to every object created by the provider.",
+			},
+			"operation_timeout": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Maximum duration of each resource and data source operation (e.g., 5m). Unlimited if not set.",
+			},
+			"audit_file": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Path of a file where every change performed by the provider on the clusters is appended, as one JSON record per line.",
+			},
+			"record_events": {
+				Type:        types.BoolType,
+				Optional:    true,
+				Description: "Whether to record a Kubernetes event on the objects created, updated or deleted by the provider.",
+			},
+			"support_bundle_dir": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Directory where a diagnostic snapshot of the cluster is collected when a peering fails, to be attached to support requests.",
+			},
+			"validate_connection": {
+				Type:        types.BoolType,
+				Optional:    true,
+				Description: "Whether to verify the cluster credentials and the Liqo installation when the provider is configured.",
+			},
+			"kubernetes": {
+				Optional:    true,
+				Computed:    true,
+				Attributes:  tfsdk.SingleNestedAttributes(kubernetesAttributes(true, true)),
+				Description: "Connection to the cluster managed by the provider.",
+			},
+			"clusters": {
+				Optional:    true,
+				Attributes:  tfsdk.MapNestedAttributes(kubernetesAttributes(true, false)),
+				Description: "Named connections to additional clusters, selected through the cluster attribute of resources and data sources.",
 			},
 		},
 	}, nil
 }
 
+// kubernetesAttributes returns the attributes describing the connection to a cluster.
+// Resource schemas disable the defaults, which would otherwise require the attributes to be computed.
+// withEnv documents the KUBE_* environment variables, which only the default connection reads.
+func kubernetesAttributes(withDefaults, withEnv bool) map[string]tfsdk.Attribute {
+	defaults := func(modifiers ...tfsdk.AttributePlanModifier) []tfsdk.AttributePlanModifier {
+		if !withDefaults {
+			return nil
+		}
+		return modifiers
+	}
+	env := func(description, variables string) string {
+		if !withEnv {
+			return description
+		}
+		return description + " Can be set with " + variables + "."
+	}
+
+	return map[string]tfsdk.Attribute{
+		"in_cluster": {
+			Type:          types.BoolType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultBool(false)),
+			Description:   "Whether to use the service account of the pod the provider is running in. All other settings are ignored.",
+		},
+		"host": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("The hostname (in form of URI) of Kubernetes master.", "KUBE_HOST"),
+		},
+		"username": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("The username to use for HTTP basic authentication when accessing the Kubernetes master endpoint.", "KUBE_USER"),
+		},
+		"password": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("The password to use for HTTP basic authentication when accessing the Kubernetes master endpoint.", "KUBE_PASSWORD"),
+		},
+		"insecure": {
+			Type:          types.BoolType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultBool(false)),
+			Description:   env("Whether server should be accessed without verifying the TLS certificate.", "KUBE_INSECURE"),
+		},
+		"tls_server_name": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Server name used to verify the TLS certificate of the API server.", "KUBE_TLS_SERVER_NAME"),
+		},
+		"client_certificate": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("PEM-encoded client certificate for TLS authentication.", "KUBE_CLIENT_CERT_DATA"),
+		},
+		"client_certificate_file": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Path to a PEM-encoded client certificate for TLS authentication.", "KUBE_CLIENT_CERT_FILE"),
+		},
+		"client_key": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("PEM-encoded client certificate key for TLS authentication.", "KUBE_CLIENT_KEY_DATA"),
+		},
+		"client_key_file": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Path to a PEM-encoded client certificate key for TLS authentication.", "KUBE_CLIENT_KEY_FILE"),
+		},
+		"cluster_ca_certificate": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("PEM-encoded root certificates bundle for TLS authentication.", "KUBE_CLUSTER_CA_CERT_DATA"),
+		},
+		"cluster_ca_certificate_file": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Path to a PEM-encoded root certificates bundle for TLS authentication.", "KUBE_CLUSTER_CA_CERT_FILE"),
+		},
+		"config_paths": {
+			Type:          types.ListType{ElemType: types.StringType},
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultValue(types.ListNull(types.StringType))),
+			Description:   env("A list of paths to kube config files.", "KUBE_CONFIG_PATHS or KUBECONFIG"),
+		},
+		"config_path": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Path to the kube config file.", "KUBE_CONFIG_PATH"),
+		},
+		"config_content": {
+			Type:          types.StringType,
+			Optional:      true,
+			Sensitive:     true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Content of the kube config file, used instead of config_path and config_paths.", "KUBE_CONFIG_CONTENT"),
+		},
+		"config_context": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Context to choose from the kube config file.", "KUBE_CTX"),
+		},
+		"config_context_auth_info": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Authentication info context of the kube config.", "KUBE_CTX_AUTH_INFO"),
+		},
+		"config_context_cluster": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Cluster context of the kube config.", "KUBE_CTX_CLUSTER"),
+		},
+		"token": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Token to authenticate an service account.", "KUBE_TOKEN"),
+		},
+		"proxy_url": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("URL to the proxy to be used for all API requests.", "KUBE_PROXY_URL"),
+		},
+		"as": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("Username to impersonate for the operations.", "KUBE_IMPERSONATE"),
+		},
+		"as_groups": {
+			Type:          types.ListType{ElemType: types.StringType},
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultValue(types.ListNull(types.StringType))),
+			Description:   "Groups to impersonate for the operations.",
+		},
+		"as_uid": {
+			Type:          types.StringType,
+			Optional:      true,
+			PlanModifiers: defaults(planmodifier.DefaultString("")),
+			Description:   env("UID to impersonate for the operations.", "KUBE_IMPERSONATE_UID"),
+		},
+		"aws_eks": {
+			Optional: true,
+			Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+				"cluster_name": {
+					Type:        types.StringType,
+					Required:    true,
+					Description: "Name of the EKS cluster.",
+				},
+				"region": {
+					Type:        types.StringType,
+					Optional:    true,
+					Description: "AWS region of the EKS cluster. Defaults to the region of the AWS configuration.",
+				},
+				"profile": {
+					Type:        types.StringType,
+					Optional:    true,
+					Description: "AWS shared configuration profile used to obtain the credentials.",
+				},
+				"role_arn": {
+					Type:        types.StringType,
+					Optional:    true,
+					Description: "ARN of the IAM role to assume before generating the token.",
+				},
+			}),
+			Description: "Authenticate against an EKS cluster with tokens generated from the AWS credentials, without external exec plugins.",
+		},
+		"qps": {
+			Type:     types.Float64Type,
+			Optional: true,
+			Validators: []tfsdk.AttributeValidator{
+				float64validator.AtLeast(0),
+			},
+			Description: "Maximum queries per second sent by the clients to the API server. Defaults to the client-go value.",
+		},
+		"burst": {
+			Type:     types.Int64Type,
+			Optional: true,
+			Validators: []tfsdk.AttributeValidator{
+				int64validator.AtLeast(0),
+			},
+			Description: "Maximum burst of queries sent by the clients to the API server. Defaults to the client-go value.",
+		},
+		"request_timeout": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: env("Timeout of each request to the API server (e.g., 30s). Unlimited if not set.", "KUBE_REQUEST_TIMEOUT"),
+		},
+		"exec": {
+			Optional: true,
+			Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+				"api_version": {
+					Type:          types.StringType,
+					Required:      true,
+					PlanModifiers: defaults(planmodifier.DefaultString("")),
+					Validators: []tfsdk.AttributeValidator{
+						stringvalidator.NoneOf("client.authentication.k8s.io/v1alpha1"),
+					},
+				},
+				"command": {
+					Type:          types.StringType,
+					Required:      true,
+					PlanModifiers: defaults(planmodifier.DefaultString("")),
+				},
+				"env": {
+					Type:          types.MapType{ElemType: types.StringType},
+					Optional:      true,
+					PlanModifiers: defaults(planmodifier.DefaultValue(types.MapNull(types.StringType))),
+				},
+				"args": {
+					Type:          types.ListType{ElemType: types.StringType},
+					Optional:      true,
+					PlanModifiers: defaults(planmodifier.DefaultValue(types.ListNull(types.StringType))),
+				},
+			}),
+		},
+	}
+}
+
+// ValidateConfig method to check that mutually exclusive kubernetes settings are not configured together.
+//
+//nolint:gocritic // Terraform Framework template code
+func (p *liqoProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var config liqoProviderModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isSet(config.OperationTimeout) {
+		if _, err := time.ParseDuration(config.OperationTimeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("operation_timeout"),
+				"Invalid Provider Configuration",
+				err.Error(),
+			)
+		}
+	}
+
+	// Unknown settings are converted to empty values, as they are validated once known.
+	defaultKube, diags := config.defaultKubeConf(ctx)
+	resp.Diagnostics.Append(diags...)
+	clusters, diags := config.clusterKubeConfs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if defaultKube != nil {
+		validateKubeConf(defaultKube, path.Root("kubernetes"), &resp.Diagnostics)
+	}
+	for name := range clusters {
+		kube := clusters[name]
+		validateKubeConf(&kube, path.Root("clusters").AtMapKey(name), &resp.Diagnostics)
+	}
+}
+
+// validateKubeConf checks the settings of a single cluster connection, reporting errors relative to the given path.
+func validateKubeConf(kube *kubeConf, base path.Path, diags *diag.Diagnostics) {
+	type setting struct {
+		name string
+		set  bool
+	}
+
+	exclusive := [][]setting{
+		{{"config_path", isSet(kube.KubeConfigPath)}, {"config_paths", len(kube.KubeConfigPaths) > 0}, {"config_content", isSet(kube.KubeConfigContent)}},
+		{{"cluster_ca_certificate", isSet(kube.KubeClusterCaCertData)}, {"cluster_ca_certificate_file", isSet(kube.KubeClusterCaCertFile)}},
+		{{"client_certificate", isSet(kube.KubeClientCertData)}, {"client_certificate_file", isSet(kube.KubeClientCertFile)}},
+		{{"client_key", isSet(kube.KubeClientKeyData)}, {"client_key_file", isSet(kube.KubeClientKeyFile)}},
+		{{"token", isSet(kube.KubeToken)}, {"exec", kube.KubeExec != nil}, {"aws_eks", kube.KubeAWSEKS != nil}},
+	}
+
+	for _, group := range exclusive {
+		var names []string
+		for _, s := range group {
+			if s.set {
+				names = append(names, s.name)
+			}
+		}
+
+		if len(names) > 1 {
+			diags.AddAttributeError(
+				base.AtName(names[1]),
+				"Conflicting Kubernetes Configuration",
+				fmt.Sprintf("Only one of %s can be set.", strings.Join(names, ", ")),
+			)
+		}
+	}
+
+	if kube.KubeInsecure.ValueBool() && (isSet(kube.KubeClusterCaCertData) || isSet(kube.KubeClusterCaCertFile)) {
+		diags.AddAttributeError(
+			base.AtName("insecure"),
+			"Conflicting Kubernetes Configuration",
+			"insecure cannot be enabled when a cluster CA certificate is configured.",
+		)
+	}
+
+	if kube.InCluster.ValueBool() &&
+		(isSet(kube.KubeConfigPath) || len(kube.KubeConfigPaths) > 0 || isSet(kube.KubeConfigContent) || isSet(kube.KubeHost)) {
+		diags.AddAttributeError(
+			base.AtName("in_cluster"),
+			"Conflicting Kubernetes Configuration",
+			"in_cluster cannot be enabled together with config_path, config_paths, config_content or host.",
+		)
+	}
+
+	if isSet(kube.KubeRequestTimeout) {
+		if _, err := time.ParseDuration(kube.KubeRequestTimeout.ValueString()); err != nil {
+			diags.AddAttributeError(
+				base.AtName("request_timeout"),
+				"Invalid Kubernetes Configuration",
+				err.Error(),
+			)
+		}
+	}
+}
+
+// isSet returns whether the given attribute is configured with a known, non-empty value.
+func isSet(attribute types.String) bool {
+	return !attribute.IsNull() && !attribute.IsUnknown() && attribute.ValueString() != ""
+}
+
 // Configure method to create the two kubernetes Clients using parameters passed in the provider instantiation in Terraform main
 // After the creation both Clients will be available in resources and data sources.
 //
@@ -364,16 +738,155 @@ func (p *liqoProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		return
 	}
 
-	resp.ResourceData = config
+	data := &liqoProviderData{
+		config:    config,
+		userAgent: fmt.Sprintf("Terraform/%s terraform-provider-liqo/%s", req.TerraformVersion, p.version),
+		scheme:    newScheme(),
+	}
+	if !config.MaxConcurrentOperations.IsNull() && !config.MaxConcurrentOperations.IsUnknown() {
+		data.limiter = make(chan struct{}, config.MaxConcurrentOperations.ValueInt64())
+	}
+	if isSet(config.AuditFile) {
+		auditPath, err := homedir.Expand(config.AuditFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("audit_file"),
+				"Invalid Provider Configuration",
+				err.Error(),
+			)
+			return
+		}
+		data.auditLog = &auditLog{path: auditPath}
+	}
+	if isSet(config.SupportBundleDir) {
+		bundleDir, err := homedir.Expand(config.SupportBundleDir.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("support_bundle_dir"),
+				"Invalid Provider Configuration",
+				err.Error(),
+			)
+			return
+		}
+		data.supportBundleDir = bundleDir
+	}
+	if isSet(config.OperationTimeout) {
+		timeout, err := time.ParseDuration(config.OperationTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("operation_timeout"),
+				"Invalid Provider Configuration",
+				err.Error(),
+			)
+			return
+		}
+		data.operationTimeout = timeout
+	}
+
+	if !config.DefaultLabels.IsNull() && !config.DefaultLabels.IsUnknown() {
+		resp.Diagnostics.Append(config.DefaultLabels.ElementsAs(ctx, &data.defaultLabels, false)...)
+	}
+	if !config.DefaultAnnotations.IsNull() && !config.DefaultAnnotations.IsUnknown() {
+		resp.Diagnostics.Append(config.DefaultAnnotations.ElementsAs(ctx, &data.defaultAnnotations, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The connection settings may depend on resources not created yet, such as a cluster provisioned in the same run.
+	// In this case, the clients are created when the provider is configured again during the apply.
+	if !isFullyKnown(ctx, config.Kubernetes) || !isFullyKnown(ctx, config.Clusters) {
+		data.deferred = true
+		resp.DataSourceData = data
+		resp.ResourceData = data
+		return
+	}
+
+	defaultKube, diags := config.defaultKubeConf(ctx)
+	resp.Diagnostics.Append(diags...)
+	clusters, diags := config.clusterKubeConfs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The default connection is optional when all the clusters are declared by name.
+	if defaultKube != nil || len(clusters) == 0 {
+		clients, err := data.newClusterClients(defaultKube, true)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create Kubernetes Clients",
+				newRedactor(defaultKube.secrets()...).Error(err),
+			)
+			return
+		}
+		data.CRClient, data.KubeClient = clients.CRClient, clients.KubeClient
+	}
+
+	data.clusters = make(map[string]*clusterClients, len(clusters))
+	for name := range clusters {
+		kube := clusters[name]
+		clients, err := data.newClusterClients(&kube, false)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("clusters").AtMapKey(name),
+				"Unable to Create Kubernetes Clients",
+				newRedactor(kube.secrets()...).Error(err),
+			)
+			return
+		}
+		data.clusters[name] = clients
+	}
+
+	if config.ValidateConnection.ValueBool() {
+		if data.CRClient != nil {
+			if err := validateConnection(ctx, data.CRClient, data.KubeClient); err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Connect to Cluster",
+					newRedactor(defaultKube.secrets()...).Error(err),
+				)
+				return
+			}
+		}
+		for name, clients := range data.clusters {
+			if err := validateConnection(ctx, clients.CRClient, clients.KubeClient); err != nil {
+				kube := clusters[name]
+				resp.Diagnostics.AddAttributeError(
+					path.Root("clusters").AtMapKey(name),
+					"Unable to Connect to Cluster",
+					newRedactor(kube.secrets()...).Error(err),
+				)
+				return
+			}
+		}
+	}
+
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
-func (p *liqoProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+// validateConnection checks that the cluster is reachable with the configured credentials and that Liqo is installed.
+func validateConnection(ctx context.Context, crClient client.Client, kubeClient *kubernetes.Clientset) error {
+	if err := kubeClient.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Error(); err != nil {
+		return fmt.Errorf("failed to contact the API server: %w", err)
+	}
+
+	if _, err := utils.GetClusterIdentityWithControllerClient(ctx, crClient, defaultLiqoNamespace()); err != nil {
+		return fmt.Errorf("failed to retrieve the Liqo cluster identity in namespace %q: %w", defaultLiqoNamespace(), err)
+	}
+
 	return nil
 }
 
+func (p *liqoProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		NewPeeringParametersDataSource, NewOffloadingReportDataSource, NewResourceOfferDataSource,
+	}
+}
+
 func (p *liqoProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
-		NewPeerResource, NewGenerateResource, NewOffloadResource,
+		NewPeerResource, NewGenerateResource, NewOffloadResource, NewClusterLabelsResource,
 	}
 }
 
@@ -384,24 +897,254 @@ type exec struct {
 	Args       []types.String `tfsdk:"args"`
 }
 
+type awsEKS struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	Region      types.String `tfsdk:"region"`
+	Profile     types.String `tfsdk:"profile"`
+	RoleARN     types.String `tfsdk:"role_arn"`
+}
+
 type kubeConf struct {
+	InCluster             types.Bool     `tfsdk:"in_cluster"`
 	KubeHost              types.String   `tfsdk:"host"`
 	KubeUser              types.String   `tfsdk:"username"`
 	KubePassword          types.String   `tfsdk:"password"`
 	KubeInsecure          types.Bool     `tfsdk:"insecure"`
+	KubeTLSServerName     types.String   `tfsdk:"tls_server_name"`
 	KubeClientCertData    types.String   `tfsdk:"client_certificate"`
+	KubeClientCertFile    types.String   `tfsdk:"client_certificate_file"`
 	KubeClientKeyData     types.String   `tfsdk:"client_key"`
+	KubeClientKeyFile     types.String   `tfsdk:"client_key_file"`
 	KubeClusterCaCertData types.String   `tfsdk:"cluster_ca_certificate"`
+	KubeClusterCaCertFile types.String   `tfsdk:"cluster_ca_certificate_file"`
 	KubeConfigPath        types.String   `tfsdk:"config_path"`
 	KubeConfigPaths       []types.String `tfsdk:"config_paths"`
+	KubeConfigContent     types.String   `tfsdk:"config_content"`
 	KubeCtx               types.String   `tfsdk:"config_context"`
 	KubeCtxAuthInfo       types.String   `tfsdk:"config_context_auth_info"`
 	KubeCtxCluster        types.String   `tfsdk:"config_context_cluster"`
 	KubeToken             types.String   `tfsdk:"token"`
 	KubeProxyURL          types.String   `tfsdk:"proxy_url"`
-	KubeExec              []exec         `tfsdk:"exec"`
+	KubeImpersonate       types.String   `tfsdk:"as"`
+	KubeImpersonateGroups []types.String `tfsdk:"as_groups"`
+	KubeImpersonateUID    types.String   `tfsdk:"as_uid"`
+	KubeExec              *exec          `tfsdk:"exec"`
+	KubeAWSEKS            *awsEKS        `tfsdk:"aws_eks"`
+	KubeQPS               types.Float64  `tfsdk:"qps"`
+	KubeBurst             types.Int64    `tfsdk:"burst"`
+	KubeRequestTimeout    types.String   `tfsdk:"request_timeout"`
 }
 
+// liqoProviderModel is the provider configuration.
+// The connections are kept as objects, since they may be unknown until apply, and are converted once known.
 type liqoProviderModel struct {
-	Kubernetes *kubeConf `tfsdk:"kubernetes"`
+	Kubernetes              types.Object `tfsdk:"kubernetes"`
+	Clusters                types.Map    `tfsdk:"clusters"`
+	MaxConcurrentOperations types.Int64  `tfsdk:"max_concurrent_operations"`
+	OperationTimeout        types.String `tfsdk:"operation_timeout"`
+	AuditFile               types.String `tfsdk:"audit_file"`
+	RecordEvents            types.Bool   `tfsdk:"record_events"`
+	SupportBundleDir        types.String `tfsdk:"support_bundle_dir"`
+	ValidateConnection      types.Bool   `tfsdk:"validate_connection"`
+	DefaultLabels           types.Map    `tfsdk:"default_labels"`
+	DefaultAnnotations      types.Map    `tfsdk:"default_annotations"`
+}
+
+// defaultKubeConf returns the default connection, or nil if it is not configured.
+func (m *liqoProviderModel) defaultKubeConf(ctx context.Context) (*kubeConf, diag.Diagnostics) {
+	return kubeConfFromObject(ctx, m.Kubernetes)
+}
+
+// clusterKubeConfs returns the named connections, skipping the ones still unknown.
+func (m *liqoProviderModel) clusterKubeConfs(ctx context.Context) (map[string]kubeConf, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	clusters := make(map[string]kubeConf, len(m.Clusters.Elements()))
+	for name, value := range m.Clusters.Elements() {
+		obj, ok := value.(types.Object)
+		if !ok {
+			continue
+		}
+
+		kube, d := kubeConfFromObject(ctx, obj)
+		diags.Append(d...)
+		if kube != nil {
+			clusters[name] = *kube
+		}
+	}
+
+	return clusters, diags
+}
+
+// kubeConfFromObject converts the given connection, returning nil if it is null or unknown.
+// Unknown nested settings are converted to empty values, hence the object shall be fully known to create the clients.
+func kubeConfFromObject(ctx context.Context, obj types.Object) (*kubeConf, diag.Diagnostics) {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, nil
+	}
+
+	var kube kubeConf
+	diags := obj.As(ctx, &kube, types.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return &kube, diags
+}
+
+// isFullyKnown returns whether the given value, including the nested ones, is known.
+func isFullyKnown(ctx context.Context, value attr.Value) bool {
+	tfValue, err := value.ToTerraformValue(ctx)
+	return err == nil && tfValue.IsFullyKnown()
+}
+
+// liqoProviderData is the data shared by the provider with resources and data sources.
+// The Kubernetes clients are created once, and reused by all the operations.
+type liqoProviderData struct {
+	CRClient   client.Client
+	KubeClient *kubernetes.Clientset
+
+	clusters           map[string]*clusterClients
+	config             liqoProviderModel
+	userAgent          string
+	scheme             *runtime.Scheme
+	limiter            chan struct{}
+	operationTimeout   time.Duration
+	deferred           bool
+	auditLog           *auditLog
+	supportBundleDir   string
+	defaultLabels      map[string]string
+	defaultAnnotations map[string]string
+	health             peeringHealthSummary
+}
+
+// applyDefaultMetadata stamps the default labels and annotations on the given object, without overriding existing keys.
+func (d *liqoProviderData) applyDefaultMetadata(obj metav1.Object) {
+	if len(d.defaultLabels) > 0 {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range d.defaultLabels {
+			if _, found := labels[k]; !found {
+				labels[k] = v
+			}
+		}
+		obj.SetLabels(labels)
+	}
+
+	if len(d.defaultAnnotations) > 0 {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range d.defaultAnnotations {
+			if _, found := annotations[k]; !found {
+				annotations[k] = v
+			}
+		}
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// clusterClients are the Kubernetes clients of a cluster declared in the provider clusters.
+type clusterClients struct {
+	CRClient   client.Client
+	KubeClient *kubernetes.Clientset
+}
+
+// errConnectionDeferred is returned when the provider connections depend on values not known until apply.
+var errConnectionDeferred = errors.New("the provider connection depends on values not known until apply")
+
+// clients returns the Kubernetes clients of the given cluster, or the ones of the default connection if not set.
+func (d *liqoProviderData) clients(cluster types.String) (client.Client, *kubernetes.Clientset, error) {
+	if d.deferred {
+		return nil, nil, errConnectionDeferred
+	}
+
+	if !isSet(cluster) {
+		if d.CRClient == nil {
+			return nil, nil, errors.New("no default kubernetes connection is configured in the provider, set cluster")
+		}
+		return d.CRClient, d.KubeClient, nil
+	}
+
+	clients, found := d.clusters[cluster.ValueString()]
+	if !found {
+		return nil, nil, fmt.Errorf("cluster %q is not declared in the provider clusters", cluster.ValueString())
+	}
+	return clients.CRClient, clients.KubeClient, nil
+}
+
+// newClusterClients creates the Kubernetes clients for the given connection.
+// Only the default connection sets useEnv, to fill the unset settings from the KUBE_* environment variables.
+func (d *liqoProviderData) newClusterClients(kube *kubeConf, useEnv bool) (*clusterClients, error) {
+	restCfg, err := d.restConfig(kube, useEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	CRClient, KubeClient, err := NewClients(restCfg, d.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clusterClients{CRClient: CRClient, KubeClient: KubeClient}, nil
+}
+
+// restConfig builds the rest configuration of the provider clients, identifying the provider through the user agent.
+func (d *liqoProviderData) restConfig(kube *kubeConf, useEnv bool) (*rest.Config, error) {
+	restCfg, err := RESTConfig(kube, useEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	restCfg.UserAgent = d.userAgent
+
+	requestTimeout := types.StringNull()
+	if kube != nil {
+		if !kube.KubeQPS.IsNull() && !kube.KubeQPS.IsUnknown() {
+			restCfg.QPS = float32(kube.KubeQPS.ValueFloat64())
+		}
+		if !kube.KubeBurst.IsNull() && !kube.KubeBurst.IsUnknown() {
+			restCfg.Burst = int(kube.KubeBurst.ValueInt64())
+		}
+		requestTimeout = kube.KubeRequestTimeout
+	}
+
+	requestTimeoutEnv := []string{}
+	if useEnv {
+		requestTimeoutEnv = append(requestTimeoutEnv, "KUBE_REQUEST_TIMEOUT")
+	}
+	if v, ok := envString(requestTimeout, requestTimeoutEnv...); ok {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request timeout %q: %w", v, err)
+		}
+		restCfg.Timeout = timeout
+	}
+
+	return restCfg, nil
+}
+
+// withTimeout returns the context of an operation, bounded by the configured operation timeout.
+func (d *liqoProviderData) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.operationTimeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, d.operationTimeout)
+}
+
+// acquire waits for an operation slot to be available, and returns the function to release it.
+func (d *liqoProviderData) acquire(ctx context.Context) (func(), error) {
+	if d.limiter == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case d.limiter <- struct{}{}:
+		return func() { <-d.limiter }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
@@ -0,0 +1,60 @@
+package liqo
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// redactedPlaceholder replaces the sensitive values in the messages surfaced to the user.
+const redactedPlaceholder = "<redacted>"
+
+// redactor removes known sensitive values, such as tokens, kube config contents and authentication endpoints,
+// from the messages reported in the diagnostics.
+type redactor struct {
+	secrets []string
+}
+
+// newRedactor returns a redactor for the given values, ignoring the empty ones.
+func newRedactor(secrets ...string) *redactor {
+	r := &redactor{}
+	for _, secret := range secrets {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	return r
+}
+
+// String returns the given message with all the sensitive values replaced.
+func (r *redactor) String(msg string) string {
+	for _, secret := range r.secrets {
+		msg = strings.ReplaceAll(msg, secret, redactedPlaceholder)
+	}
+	return msg
+}
+
+// Error returns the message of the given error, with the troubleshooting hint, and all the sensitive values replaced.
+func (r *redactor) Error(err error) string {
+	return r.String(describeError(err))
+}
+
+// secrets returns the sensitive values of a cluster connection, either configured or read from the environment.
+func (k *kubeConf) secrets() []string {
+	if k == nil {
+		k = &kubeConf{}
+	}
+
+	var secrets []string
+	add := func(attribute types.String, env string) {
+		if v, ok := envString(attribute, env); ok {
+			secrets = append(secrets, v)
+		}
+	}
+
+	add(k.KubePassword, "KUBE_PASSWORD")
+	add(k.KubeToken, "KUBE_TOKEN")
+	add(k.KubeClientKeyData, "KUBE_CLIENT_KEY_DATA")
+	add(k.KubeConfigContent, "KUBE_CONFIG_CONTENT")
+	return secrets
+}
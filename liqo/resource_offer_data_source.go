@@ -0,0 +1,178 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var (
+	_ datasource.DataSource              = &resourceOfferDataSource{}
+	_ datasource.DataSourceWithConfigure = &resourceOfferDataSource{}
+)
+
+// NewResourceOfferDataSource provides the initialization of Resource Offer Data Source.
+func NewResourceOfferDataSource() datasource.DataSource {
+	return &resourceOfferDataSource{}
+}
+
+type resourceOfferDataSource struct {
+	data *liqoProviderData
+}
+
+func (d *resourceOfferDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_resource_offer"
+}
+
+func (d *resourceOfferDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		Description: "Retrieve the resources a peered cluster is willing to share with the local one, as advertised by its ResourceOffer. " +
+			"The offer only exists once the outgoing peering is established, so it cannot be used to decide whether to peer: " +
+			"read it after a liqo_peer resource, e.g. through depends_on.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Name of the provider clusters entry to operate on. Defaults to the kubernetes connection.",
+			},
+			"cluster_id": {
+				Type:        types.StringType,
+				Required:    true,
+				Description: "ID of the remote cluster offering the resources.",
+			},
+			"cpu": {
+				Type:        types.StringType,
+				Computed:    true,
+				Description: "CPU offered by the remote cluster.",
+			},
+			"memory": {
+				Type:        types.StringType,
+				Computed:    true,
+				Description: "Memory offered by the remote cluster.",
+			},
+			"pods": {
+				Type:        types.StringType,
+				Computed:    true,
+				Description: "Number of pods offered by the remote cluster.",
+			},
+			"resources": {
+				Type:        types.MapType{ElemType: types.StringType},
+				Computed:    true,
+				Description: "All the resources offered by the remote cluster, keyed by resource name.",
+			},
+			"labels": {
+				Type:        types.MapType{ElemType: types.StringType},
+				Computed:    true,
+				Description: "Labels advertised by the remote cluster, applied to the corresponding virtual node.",
+			},
+		},
+	}, nil
+}
+
+// Read retrieves the ResourceOffer received from the remote cluster, which is available once the outgoing peering is established.
+//
+//nolint:gocritic // Terraform Framework template code
+func (d *resourceOfferDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data resourceOfferDataSourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := d.data.withTimeout(ctx)
+	defer cancel()
+
+	release, err := d.data.acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+	defer release()
+
+	CRClient, _, err := d.data.clients(data.Cluster)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+
+	offer, err := getResourceOffer(ctx, CRClient, data.ClusterID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Data Source",
+			describeError(err),
+		)
+		return
+	}
+
+	hard := offer.Spec.ResourceQuota.Hard
+	resources := make(map[string]string, len(hard))
+	for name, quantity := range hard {
+		resources[string(name)] = quantity.String()
+	}
+
+	data.CPU = types.StringValue(hard.Cpu().String())
+	data.Memory = types.StringValue(hard.Memory().String())
+	data.Pods = types.StringValue(hard.Pods().String())
+	data.Resources, diags = types.MapValueFrom(ctx, types.StringType, resources)
+	resp.Diagnostics.Append(diags...)
+	data.Labels, diags = types.MapValueFrom(ctx, types.StringType, offer.Spec.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Configure method to obtain kubernetes Clients provided by provider.
+func (d *resourceOfferDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.data = req.ProviderData.(*liqoProviderData)
+}
+
+// getResourceOffer returns the ResourceOffer replicated from the given remote cluster.
+func getResourceOffer(ctx context.Context, cl client.Client, clusterID string) (*sharingv1alpha1.ResourceOffer, error) {
+	var offers sharingv1alpha1.ResourceOfferList
+	if err := cl.List(ctx, &offers, client.MatchingLabels{consts.ReplicationOriginLabel: clusterID}); err != nil {
+		return nil, err
+	}
+
+	for i := range offers.Items {
+		if offers.Items[i].Spec.WithdrawalTimestamp.IsZero() {
+			return &offers.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no resources offered by remote cluster %q, make sure the outgoing peering is established", clusterID)
+}
+
+type resourceOfferDataSourceModel struct {
+	Cluster   types.String `tfsdk:"cluster"`
+	ClusterID types.String `tfsdk:"cluster_id"`
+	CPU       types.String `tfsdk:"cpu"`
+	Memory    types.String `tfsdk:"memory"`
+	Pods      types.String `tfsdk:"pods"`
+	Resources types.Map    `tfsdk:"resources"`
+	Labels    types.Map    `tfsdk:"labels"`
+}
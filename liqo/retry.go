@@ -0,0 +1,55 @@
+package liqo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// transientBackoff is the backoff used to retry the errors occurring while Liqo is still starting up, for about one minute.
+var transientBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+}
+
+// isTransientError returns whether the error has been caused by a webhook not ready yet,
+// as it happens right after the installation of Liqo, until the webhook certificates are in place.
+func isTransientError(err error) bool {
+	// Only the errors returned by the API server are considered, as local TLS failures are not transient.
+	var status kerrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+
+	msg := status.Status().Message
+	return strings.Contains(msg, "failed calling webhook") ||
+		strings.Contains(msg, "conversion webhook") ||
+		strings.Contains(msg, "x509:")
+}
+
+// retryOnTransientError executes the given function, retrying it as long as it fails with transient errors.
+func retryOnTransientError(ctx context.Context, fn func() error) error {
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, transientBackoff, func(ctx context.Context) (bool, error) {
+		lastErr = fn()
+		switch {
+		case lastErr == nil:
+			return true, nil
+		case isTransientError(lastErr):
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	})
+
+	if wait.Interrupted(err) && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
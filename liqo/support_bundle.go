@@ -0,0 +1,102 @@
+package liqo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+)
+
+// supportBundleTimeout bounds the collection of a support bundle, which may follow the expiration of the operation.
+const supportBundleTimeout = 30 * time.Second
+
+// supportBundle collects a diagnostic snapshot of the clusters after a peering failure, if a support bundle directory is configured.
+// The remote cluster is captured as well, if its connection is set.
+// It returns the sentence to append to the error diagnostic, referencing the bundle.
+func (d *liqoProviderData) supportBundle(ctx context.Context, cluster types.String, remote *kubeConf, liqoNamespace, clusterID string) string {
+	if d.supportBundleDir == "" {
+		return ""
+	}
+
+	// The operation context may have already expired, as in case of timeouts, hence the collection gets a dedicated one.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), supportBundleTimeout)
+	defer cancel()
+
+	dir, err := d.collectSupportBundle(ctx, cluster, remote, liqoNamespace, clusterID)
+	if err != nil {
+		return fmt.Sprintf("\n\nFailed to collect the support bundle: %s", err)
+	}
+
+	return fmt.Sprintf("\n\nA support bundle has been collected in %s.", dir)
+}
+
+func (d *liqoProviderData) collectSupportBundle(ctx context.Context, cluster types.String, remote *kubeConf,
+	liqoNamespace, clusterID string) (string, error) {
+	dir := filepath.Join(d.supportBundleDir,
+		fmt.Sprintf("liqo-peer-%s-%s", clusterID, time.Now().UTC().Format("20060102T150405Z")))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	// The collection goes on in case of errors, as a partial snapshot is still valuable.
+	var failures []string
+	if CRClient, _, err := d.clients(cluster); err != nil {
+		failures = append(failures, fmt.Sprintf("local: %s", err))
+	} else {
+		failures = append(failures, collectClusterSnapshot(ctx, CRClient, filepath.Join(dir, "local"), liqoNamespace)...)
+	}
+
+	if remote != nil {
+		if clients, err := d.newClusterClients(remote, false); err != nil {
+			failures = append(failures, fmt.Sprintf("remote: %s", newRedactor(remote.secrets()...).Error(err)))
+		} else {
+			failures = append(failures, collectClusterSnapshot(ctx, clients.CRClient, filepath.Join(dir, "remote"), liqoNamespace)...)
+		}
+	}
+
+	if len(failures) > 0 {
+		if err := os.WriteFile(filepath.Join(dir, "errors.txt"), []byte(strings.Join(failures, "\n")+"\n"), 0o600); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// collectClusterSnapshot writes the Liqo objects of a cluster to the given directory, returning the failures encountered.
+func collectClusterSnapshot(ctx context.Context, cl client.Client, dir, liqoNamespace string) []string {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return []string{fmt.Sprintf("%s: %s", dir, err)}
+	}
+
+	var failures []string
+	collect := func(name string, list client.ObjectList, opts ...client.ListOption) {
+		if err := cl.List(ctx, list, opts...); err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", filepath.Base(dir), name, err))
+			return
+		}
+
+		content, err := yaml.Marshal(list)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, name+".yaml"), content, 0o600)
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", filepath.Base(dir), name, err))
+		}
+	}
+
+	collect("foreignclusters", &discoveryv1alpha1.ForeignClusterList{})
+	collect("pods", &corev1.PodList{}, client.InNamespace(liqoNamespace))
+	collect("events", &corev1.EventList{}, client.InNamespace(liqoNamespace))
+
+	return failures
+}
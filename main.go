@@ -3,6 +3,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
@@ -12,9 +14,20 @@ import (
 // Provider documentation generation.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate --provider-name liqo
 
+// version is set by goreleaser at build time.
+var version = "dev"
+
 func main() {
-	//nolint:errcheck,gosec // Terraform Framework template code
-	providerserver.Serve(context.Background(), liqo.New, providerserver.ServeOpts{
-		Address: "liqo-provider/liqo/test",
+	var debug bool
+
+	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	err := providerserver.Serve(context.Background(), liqo.New(version), providerserver.ServeOpts{
+		Address: "registry.terraform.io/liqotech/liqo",
+		Debug:   debug,
 	})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 }